@@ -0,0 +1,266 @@
+// Package batch procesa muchos CFDI en paralelo con un pool de workers acotado.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+// Result es el resultado de procesar un Source.
+type Result struct {
+	// Index es la posición del Source en la lista de entrada.
+	Index int
+	Path  string
+	Data  *models.CFDI40Data
+	Err   error
+}
+
+// Progress describe el avance del procesamiento.
+type Progress struct {
+	Total  int
+	Done   int
+	Failed int
+	// Path es el último documento terminado.
+	Path string
+}
+
+// Options contiene las opciones del Processor.
+type Options struct {
+	// Workers es el numero de documentos procesados al mismo tiempo. Por defecto runtime.NumCPU().
+	Workers int
+	// Ordered entrega los resultados en el orden de entrada; si es false se entregan en orden de llegada.
+	// Para no acumular resultados detras de un documento lento, a lo mas ReorderWindow veces
+	// Workers documentos se procesan o esperan su turno al mismo tiempo.
+	Ordered bool
+	// OnProgress se llama cada vez que termina un documento, siempre desde la misma goroutine.
+	OnProgress func(Progress)
+}
+
+// ReorderWindow es el numero de documentos por worker que pueden esperar su turno en el modo Ordered.
+const ReorderWindow = 4
+
+// Processor parsea lotes de CFDI 4.0 con una configuración compartida.
+type Processor struct {
+	config  sax.HandlerConfig
+	options Options
+}
+
+// NewProcessor creates a new Processor with the given handler configuration and options.
+func NewProcessor(config sax.HandlerConfig, options Options) *Processor {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	return &Processor{config: config, options: options}
+}
+
+// Process parses the sources concurrently and streams the results through the returned channel.
+// The channel is closed when every source has been processed or ctx is cancelled.
+func (p *Processor) Process(ctx context.Context, sources []Source) <-chan Result {
+	jobs := make(chan int)
+	results := make(chan Result, p.options.Workers)
+	out := make(chan Result, p.options.Workers)
+
+	// In ordered mode a slot is taken before dispatching a source and released when its
+	// result is delivered, so the results waiting for an earlier document are bounded.
+	var slots chan struct{}
+	if p.options.Ordered {
+		slots = make(chan struct{}, p.options.Workers*ReorderWindow)
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range sources {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < p.options.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler := sax.NewCFDI40Handler(p.config)
+			for i := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				data, err := transform(handler, sources[i])
+				select {
+				case results <- Result{Index: i, Path: sources[i].Path, Data: data, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(out)
+		p.dispatch(ctx, len(sources), results, out, slots)
+		// Wait for the workers so no source is still open when out is closed.
+		for range results {
+		}
+	}()
+
+	return out
+}
+
+// ProcessDir parses every XML file found under dir.
+func (p *Processor) ProcessDir(ctx context.Context, dir string) (<-chan Result, error) {
+	sources, err := DirSources(dir)
+	if err != nil {
+		return nil, err
+	}
+	return p.Process(ctx, sources), nil
+}
+
+// ProcessZip parses every XML entry of the zip file at path without extracting it to disk.
+func (p *Processor) ProcessZip(ctx context.Context, path string) (<-chan Result, error) {
+	archive, err := OpenZip(path)
+	if err != nil {
+		return nil, err
+	}
+
+	in := p.Process(ctx, archive.Sources())
+	out := make(chan Result)
+	go func() {
+		defer archive.Close()
+		defer close(out)
+		for res := range in {
+			select {
+			case out <- res:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+// dispatch forwards the results to out, reordering them if needed, and reports progress.
+// In ordered mode it releases a slot for every result delivered.
+func (p *Processor) dispatch(ctx context.Context, total int, results <-chan Result, out chan<- Result, slots chan struct{}) {
+	progress := Progress{Total: total}
+	pending := map[int]Result{}
+	next := 0
+
+	send := func(res Result) bool {
+		select {
+		case out <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for res := range results {
+		progress.Done++
+		progress.Path = res.Path
+		if res.Err != nil {
+			progress.Failed++
+		}
+		if p.options.OnProgress != nil {
+			p.options.OnProgress(progress)
+		}
+
+		if !p.options.Ordered {
+			if !send(res) {
+				return
+			}
+			continue
+		}
+
+		pending[res.Index] = res
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if !send(r) {
+				return
+			}
+			<-slots
+		}
+	}
+}
+
+func transform(handler *sax.CFDI40Handler, source Source) (*models.CFDI40Data, error) {
+	reader, err := source.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer reader.Close()
+
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, reader); err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return handler.TransformFromString(sb.String())
+}
+
+// FileError es el error de un documento del lote.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// Errors agrega los errores por documento de un lote.
+type Errors []FileError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%d files failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Collect drains the results, returning the parsed documents and the per-file errors.
+// The returned error is nil when every document was parsed.
+func Collect(results <-chan Result) ([]models.CFDI40Data, error) {
+	var docs []models.CFDI40Data
+	var errs Errors
+	for res := range results {
+		if res.Err != nil {
+			errs = append(errs, FileError{Path: res.Path, Err: res.Err})
+			continue
+		}
+		docs = append(docs, *res.Data)
+	}
+	if len(errs) > 0 {
+		return docs, errs
+	}
+	return docs, nil
+}
@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source es un documento XML a procesar.
+type Source struct {
+	// Path identifica al documento: la ruta del archivo o la ruta dentro del zip.
+	Path string
+	// Open abre el contenido del documento.
+	Open func() (io.ReadCloser, error)
+}
+
+// FileSource creates a Source for a file on disk.
+func FileSource(path string) Source {
+	return Source{
+		Path: path,
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// DirSources walks dir recursively and returns a Source for every XML file, sorted by path.
+func DirSources(dir string) ([]Source, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isXML(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking directory: %w", err)
+	}
+
+	sort.Strings(paths)
+	sources := make([]Source, len(paths))
+	for i, path := range paths {
+		sources[i] = FileSource(path)
+	}
+	return sources, nil
+}
+
+// ZipArchive es un archivo zip abierto cuyos XML se pueden procesar sin extraerlos a disco.
+type ZipArchive struct {
+	reader *zip.ReadCloser
+}
+
+// OpenZip opens the zip file at path.
+func OpenZip(path string) (*ZipArchive, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening zip: %w", err)
+	}
+	return &ZipArchive{reader: reader}, nil
+}
+
+// Sources returns a Source for every XML entry of the archive, sorted by name.
+func (z *ZipArchive) Sources() []Source {
+	var sources []Source
+	for _, file := range z.reader.File {
+		if file.FileInfo().IsDir() || !isXML(file.Name) {
+			continue
+		}
+		f := file
+		sources = append(sources, Source{
+			Path: f.Name,
+			Open: f.Open,
+		})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Path < sources[j].Path })
+	return sources
+}
+
+// Close closes the archive.
+func (z *ZipArchive) Close() error {
+	return z.reader.Close()
+}
+
+func isXML(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".xml")
+}
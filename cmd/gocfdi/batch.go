@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/batch"
+	"github.com/sucksens/gocfdi-transform/formatters"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// fileError is an entry of the per-file error report.
+type fileError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+func runBatch(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	hf := newHandlerFlags(flags)
+	format := flags.String("format", "jsonl", "output format: jsonl or csv")
+	table := flags.String("table", formatters.TableComprobantes, "table written when -format=csv: comprobantes, conceptos, pagos, doctos_relacionados or nomina")
+	output := flags.String("o", "", "output file (default stdout)")
+	report := flags.String("errors", "", "write the per-file error report as JSONL to this file")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of files parsed concurrently")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gocfdi batch [flags] <dir|zip|glob>...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *format != "jsonl" && *format != "csv" {
+		fmt.Fprintf(stderr, "gocfdi: unknown format %q\n", *format)
+		return exitUsage
+	}
+	buildTable, ok := csvTables[*table]
+	if !ok {
+		fmt.Fprintf(stderr, "gocfdi: unknown table %q\n", *table)
+		return exitUsage
+	}
+
+	sources, archives, errs := collectSources(flags.Args())
+	defer func() {
+		for _, archive := range archives {
+			archive.Close()
+		}
+	}()
+
+	var out io.Writer = stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "error creating output file: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		out = f
+	}
+
+	processor := batch.NewProcessor(hf.config, batch.Options{Workers: *workers, Ordered: true})
+	encoder := json.NewEncoder(out)
+	var docs []models.CFDI40Data
+	parsed := 0
+
+	for res := range processor.Process(context.Background(), sources) {
+		if res.Err != nil {
+			errs = append(errs, fileError{File: res.Path, Error: res.Err.Error()})
+			continue
+		}
+		parsed++
+
+		if *format == "jsonl" {
+			if err := encoder.Encode(res.Data); err != nil {
+				fmt.Fprintf(stderr, "error writing JSON: %v\n", err)
+				return exitError
+			}
+			continue
+		}
+		docs = append(docs, *res.Data)
+	}
+
+	if *format == "csv" {
+		cfg := formatters.NewDefaultConfig()
+		cfg.EmptyChar = hf.config.EmptyChar
+		t := buildTable(formatters.NewCFDI40Formatter(cfg), docs)
+		if err := formatters.WriteCSV(out, t, cfg); err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+	}
+
+	for _, e := range errs {
+		fmt.Fprintf(stderr, "%s: %s\n", e.File, e.Error)
+	}
+	fmt.Fprintf(stderr, "%d of %d files parsed, %d errors\n", parsed, len(sources), len(errs))
+
+	if *report != "" {
+		if err := writeReport(*report, errs); err != nil {
+			fmt.Fprintf(stderr, "error writing error report: %v\n", err)
+			return exitError
+		}
+	}
+
+	if len(errs) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// collectSources expands directories, zip files and globs into the list of XML documents to parse.
+// Arguments that match nothing are returned as errors.
+func collectSources(args []string) ([]batch.Source, []*batch.ZipArchive, []fileError) {
+	var sources []batch.Source
+	var archives []*batch.ZipArchive
+	var errs []fileError
+	seen := map[string]bool{}
+
+	add := func(src batch.Source) {
+		if !seen[src.Path] {
+			seen[src.Path] = true
+			sources = append(sources, src)
+		}
+	}
+
+	addPath := func(path string) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dirSources, err := batch.DirSources(path)
+			if err != nil {
+				errs = append(errs, fileError{File: path, Error: err.Error()})
+				return
+			}
+			for _, src := range dirSources {
+				add(src)
+			}
+			return
+		}
+
+		if strings.HasSuffix(strings.ToLower(path), ".zip") {
+			archive, err := batch.OpenZip(path)
+			if err != nil {
+				errs = append(errs, fileError{File: path, Error: err.Error()})
+				return
+			}
+			archives = append(archives, archive)
+			for _, src := range archive.Sources() {
+				src.Path = path + ":" + src.Path
+				add(src)
+			}
+			return
+		}
+
+		add(batch.FileSource(path))
+	}
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			errs = append(errs, fileError{File: arg, Error: err.Error()})
+			continue
+		}
+		if len(matches) == 0 {
+			errs = append(errs, fileError{File: arg, Error: "no files matched"})
+			continue
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			addPath(match)
+		}
+	}
+
+	return sources, archives, errs
+}
+
+// csvTables contains the formatter method that builds each table accepted by -table.
+var csvTables = map[string]func(*formatters.CFDI40Formatter, []models.CFDI40Data) *formatters.Table{
+	formatters.TableComprobantes:       (*formatters.CFDI40Formatter).Comprobantes,
+	formatters.TableConceptos:          (*formatters.CFDI40Formatter).Conceptos,
+	formatters.TablePagos:              (*formatters.CFDI40Formatter).Pagos,
+	formatters.TableDoctosRelacionados: (*formatters.CFDI40Formatter).DoctosRelacionados,
+	formatters.TableNomina:             (*formatters.CFDI40Formatter).Nomina,
+}
+
+func writeReport(path string, errs []fileError) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, e := range errs {
+		if err := encoder.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
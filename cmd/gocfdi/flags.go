@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+// handlerFlags registers a flag for every HandlerConfig field.
+type handlerFlags struct {
+	config sax.HandlerConfig
+}
+
+func newHandlerFlags(fs *flag.FlagSet) *handlerFlags {
+	hf := &handlerFlags{config: sax.NewDefaultConfig()}
+	fs.StringVar(&hf.config.EmptyChar, "empty-char", hf.config.EmptyChar, "value used for empty fields")
+	fs.BoolVar(&hf.config.SafeNumerics, "safe-numerics", hf.config.SafeNumerics, "use 0.00 / 1.00 for empty numeric fields")
+	fs.StringVar(&hf.config.EscDelimiters, "esc-delimiters", hf.config.EscDelimiters, "characters removed from text fields")
+	fs.BoolVar(&hf.config.ParseConcepts, "concepts", hf.config.ParseConcepts, "parse concepts (UseConcepts)")
+	fs.BoolVar(&hf.config.ParseConceptsTaxes, "concepts-taxes", hf.config.ParseConceptsTaxes, "parse concept taxes (UseConceptsWithTaxes)")
+	fs.BoolVar(&hf.config.ParseRelatedCFDIs, "related-cfdis", hf.config.ParseRelatedCFDIs, "parse related CFDIs (UseRelatedCFDIs)")
+	fs.BoolVar(&hf.config.ParsePagos20, "pagos20", hf.config.ParsePagos20, "parse Pagos 2.0 complement (UsePagos20)")
+	fs.BoolVar(&hf.config.ParseVentaVehiculos11, "venta-vehiculos11", hf.config.ParseVentaVehiculos11, "parse Venta Vehiculos 1.1 complement (UseVentaVehiculos11)")
+	fs.BoolVar(&hf.config.ParseNomina12, "nomina12", hf.config.ParseNomina12, "parse Nomina 1.2 complement (UseNomina12)")
+	fs.BoolVar(&hf.config.ParseLeyendasFiscales10, "leyendas-fiscales10", hf.config.ParseLeyendasFiscales10, "parse Leyendas Fiscales 1.0 complement (UseLeyendasFiscales10)")
+	fs.BoolVar(&hf.config.ParseDonatarias11, "donatarias11", hf.config.ParseDonatarias11, "parse Donatarias 1.1 complement (UseDonatarias11)")
+	fs.BoolVar(&hf.config.ParseDetallista, "detallista", hf.config.ParseDetallista, "parse Detallista complement (UseDetallista)")
+	fs.BoolVar(&hf.config.ParseServicioParcial10, "servicio-parcial10", hf.config.ParseServicioParcial10, "parse Servicios Parciales de Construccion 1.0 complement (UseServicioParcial10)")
+	fs.BoolVar(&hf.config.ParseTuristaPasajeroExtranjero10, "turista-pasajero-extranjero10", hf.config.ParseTuristaPasajeroExtranjero10, "parse Turista Pasajero Extranjero 1.0 complement (UseTuristaPasajeroExtranjero10)")
+	fs.BoolVar(&hf.config.ParseValesDeDespensa10, "vales-de-despensa10", hf.config.ParseValesDeDespensa10, "parse Vales de Despensa 1.0 complement (UseValesDeDespensa10)")
+	fs.BoolVar(&hf.config.ParseIngresosHidrocarburos10, "ingresos-hidrocarburos10", hf.config.ParseIngresosHidrocarburos10, "parse Ingresos Hidrocarburos 1.0 complement (UseIngresosHidrocarburos10)")
+	fs.BoolVar(&hf.config.ParseGastosHidrocarburos10, "gastos-hidrocarburos10", hf.config.ParseGastosHidrocarburos10, "parse Gastos Hidrocarburos 1.0 complement (UseGastosHidrocarburos10)")
+	fs.BoolVar(&hf.config.ParseIEDU10, "iedu10", hf.config.ParseIEDU10, "parse Instituciones Educativas 1.0 concept complement, requires -concepts (UseIEDU10)")
+	fs.BoolVar(&hf.config.ParseRawComplements, "raw-complements", hf.config.ParseRawComplements, "keep the raw XML of unhandled complements (UseRawComplements)")
+	fs.BoolVar(&hf.config.ParseAddendas, "addendas", hf.config.ParseAddendas, "extract addenda contents (UseAddendas)")
+	fs.BoolVar(&hf.config.LenientNamespaces, "lenient-namespaces", hf.config.LenientNamespaces, "read CFDI elements of other namespaces by local name, recording warnings (UseLenientNamespaces)")
+	return hf
+}
+
+// handler returns a CFDI40Handler configured from the parsed flags.
+func (hf *handlerFlags) handler() *sax.CFDI40Handler {
+	return sax.NewCFDI40Handler(hf.config)
+}
@@ -0,0 +1,60 @@
+// Command gocfdi convierte CFDI en XML a JSON, JSONL o CSV.
+//
+// Uso:
+//
+//	gocfdi parse [flags] factura.xml
+//	gocfdi batch [flags] <directorio|glob>...
+//
+// Codigos de salida: 0 si todos los archivos se procesaron, 1 si algun archivo
+// fallo y 2 si los argumentos son invalidos.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Codigos de salida del comando.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "parse":
+		return runParse(args[1:], stdout, stderr)
+	case "batch":
+		return runBatch(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "gocfdi: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  gocfdi parse [flags] <file.xml>
+      Parse a CFDI 4.0 XML file and print it as JSON to stdout.
+
+  gocfdi batch [flags] <dir|glob>...
+      Parse every XML file found and write JSONL or CSV.
+
+Run "gocfdi <command> -h" to see the flags of each command.
+`)
+}
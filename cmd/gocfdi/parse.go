@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runParse(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	hf := newHandlerFlags(fs)
+	pretty := fs.Bool("pretty", false, "indent the JSON output")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gocfdi parse [flags] <file.xml>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	path := fs.Arg(0)
+	data, err := hf.handler().TransformFromFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return exitError
+	}
+
+	encoder := json.NewEncoder(stdout)
+	if *pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		fmt.Fprintf(stderr, "error writing JSON: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
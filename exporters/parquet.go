@@ -0,0 +1,407 @@
+// Package exporters escribe lotes de CFDI parseados en Parquet, para cargarlos en un data lake, y
+// las tablas de formatters en XLSX.
+//
+// Es un modulo aparte del principal para que solo quien exporta dependa de excelize y parquet-go.
+package exporters
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// Escalas de los decimales del esquema Parquet.
+const (
+	amountScale       = 6
+	daysScale         = 3
+	equivalenciaScale = 10
+)
+
+// ParquetWriter writes batches of CFDI40Data to a Parquet file.
+type ParquetWriter struct {
+	writer *parquet.GenericWriter[ParquetDocument]
+}
+
+// NewParquetWriter creates a new ParquetWriter that writes to w.
+// Close must be called to flush the footer of the file.
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{
+		writer: parquet.NewGenericWriter[ParquetDocument](w, parquet.Compression(&parquet.Snappy)),
+	}
+}
+
+// Write writes a batch of documents. Nothing is written when a value of any document
+// cannot be stored exactly in its decimal column.
+func (pw *ParquetWriter) Write(docs []models.CFDI40Data) error {
+	rows := make([]ParquetDocument, len(docs))
+	for i := range docs {
+		row, err := ToParquetDocument(docs[i])
+		if err != nil {
+			return err
+		}
+		rows[i] = row
+	}
+	if _, err := pw.writer.Write(rows); err != nil {
+		return fmt.Errorf("error writing parquet rows: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the pending rows and writes the footer of the file.
+func (pw *ParquetWriter) Close() error {
+	if err := pw.writer.Close(); err != nil {
+		return fmt.Errorf("error closing parquet writer: %w", err)
+	}
+	return nil
+}
+
+// WriteParquet writes the documents as a Parquet file to w.
+func WriteParquet(w io.Writer, docs []models.CFDI40Data) error {
+	pw := NewParquetWriter(w)
+	if err := pw.Write(docs); err != nil {
+		return err
+	}
+	return pw.Close()
+}
+
+// ToParquetDocument converts a CFDI40Data into its Parquet row.
+// Empty numeric values are written as null. A value that is not a decimal, has more decimal
+// places than its column or does not fit in it is reported as a *DecimalError.
+func ToParquetDocument(doc models.CFDI40Data) (ParquetDocument, error) {
+	conv := &decimalConverter{}
+	c := doc.CFDI40
+	row := ParquetDocument{
+		Version:         c.Version,
+		Serie:           c.Serie,
+		Folio:           c.Folio,
+		Fecha:           c.Fecha,
+		NoCertificado:   c.NoCertificado,
+		SubTotal:        conv.decimal("SubTotal", c.SubTotal, amountScale),
+		Descuento:       conv.decimal("Descuento", c.Descuento, amountScale),
+		Total:           conv.decimal("Total", c.Total, amountScale),
+		Moneda:          c.Moneda,
+		TipoCambio:      conv.decimal("TipoCambio", c.TipoCambio, amountScale),
+		TipoComprobante: c.TipoComprobante,
+		MetodoPago:      c.MetodoPago,
+		FormaPago:       c.FormaPago,
+		CondicionesPago: c.CondicionesPago,
+		LugarExpedicion: c.LugarExpedicion,
+		Exportacion:     c.Exportacion,
+		Confirmacion:    c.Confirmacion,
+		Emisor: ParquetEmisor{
+			RFC:              c.Emisor.RFC,
+			Nombre:           c.Emisor.Nombre,
+			RegimenFiscal:    c.Emisor.RegimenFiscal,
+			FacAtrAdquirente: c.Emisor.FacAtrAdquirente,
+		},
+		Receptor: ParquetReceptor{
+			RFC:                     c.Receptor.RFC,
+			Nombre:                  c.Receptor.Nombre,
+			DomicilioFiscalReceptor: c.Receptor.DomicilioFiscalReceptor,
+			ResidenciaFiscal:        c.Receptor.ResidenciaFiscal,
+			NumRegIdTrib:            c.Receptor.NumRegIdTrib,
+			RegimenFiscalReceptor:   c.Receptor.RegimenFiscalReceptor,
+			UsoCFDI:                 c.Receptor.UsoCFDI,
+		},
+		Impuestos: ParquetImpuestos{
+			TotalImpuestosTrasladados: conv.decimal("TotalImpuestosTrasladados", c.Impuestos.TotalImpuestosTrasladados, amountScale),
+			TotalImpuestosRetenidos:   conv.decimal("TotalImpuestosRetenidos", c.Impuestos.TotalImpuestosRetenidos, amountScale),
+		},
+		Complementos: c.Complementos,
+		Addendas:     c.Addendas,
+	}
+
+	for _, tr := range c.Impuestos.Traslados {
+		row.Impuestos.Traslados = append(row.Impuestos.Traslados, toParquetTraslado(conv, tr.Base, tr.Impuesto, tr.TipoFactor, tr.TasaOCuota, tr.Importe))
+	}
+	for _, ret := range c.Impuestos.Retenciones {
+		row.Impuestos.Retenciones = append(row.Impuestos.Retenciones, ParquetRetencion{Impuesto: ret.Impuesto, Importe: conv.decimal("Importe", ret.Importe, amountScale)})
+	}
+
+	for _, concepto := range c.Conceptos {
+		pc := ParquetConcepto{
+			ClaveProdServ:    concepto.ClaveProdServ,
+			NoIdentificacion: concepto.NoIdentificacion,
+			Cantidad:         conv.decimal("Cantidad", concepto.Cantidad, amountScale),
+			ClaveUnidad:      concepto.ClaveUnidad,
+			Unidad:           concepto.Unidad,
+			Descripcion:      concepto.Descripcion,
+			ValorUnitario:    conv.decimal("ValorUnitario", concepto.ValorUnitario, amountScale),
+			Importe:          conv.decimal("Importe", concepto.Importe, amountScale),
+			Descuento:        conv.decimal("Descuento", concepto.Descuento, amountScale),
+			ObjetoImp:        concepto.ObjetoImp,
+		}
+		for _, tr := range concepto.Traslados {
+			pc.Traslados = append(pc.Traslados, toParquetTraslado(conv, tr.Base, tr.Impuesto, tr.TipoFactor, tr.TasaOCuota, tr.Importe))
+		}
+		for _, ret := range concepto.Retenciones {
+			pc.Retenciones = append(pc.Retenciones, ParquetRetencion{Impuesto: ret.Impuesto, Importe: conv.decimal("Importe", ret.Importe, amountScale)})
+		}
+		row.Conceptos = append(row.Conceptos, pc)
+	}
+
+	for _, rel := range c.CFDIsRelacionados {
+		row.CFDIsRelacionados = append(row.CFDIsRelacionados, ParquetCFDIRelacionado{UUID: rel.UUID, TipoRelacion: rel.TipoRelacion})
+	}
+
+	if len(doc.TFD11) > 0 {
+		tfd := doc.TFD11[0]
+		row.TFD11 = &ParquetTFD11{
+			Version:          tfd.Version,
+			NoCertificadoSAT: tfd.NoCertificadoSAT,
+			UUID:             tfd.UUID,
+			FechaTimbrado:    tfd.FechaTimbrado,
+			RfcProvCert:      tfd.RfcProvCert,
+		}
+	}
+
+	for _, pagos := range doc.Pagos20 {
+		row.Pagos20 = append(row.Pagos20, toParquetPagos20(conv, pagos))
+	}
+	for _, nomina := range doc.Nomina12 {
+		row.Nomina12 = append(row.Nomina12, toParquetNomina12(conv, nomina))
+	}
+	for _, vv := range doc.VentaVehiculos11 {
+		row.VentaVehiculos11 = append(row.VentaVehiculos11, ParquetVentaVehiculos11{
+			Version:        vv.Version,
+			ClaveVehicular: vv.ClaveVehicular,
+			Niv:            vv.Niv,
+		})
+	}
+
+	if err := conv.err(); err != nil {
+		return row, fmt.Errorf("document %s: %w", firstUUID(doc), err)
+	}
+	return row, nil
+}
+
+func toParquetPagos20(conv *decimalConverter, pagos models.Pagos20Data) ParquetPagos20 {
+	t := pagos.Totales
+	row := ParquetPagos20{
+		Version: pagos.Version,
+		Totales: ParquetTotales20{
+			TotalRetencionesIVA:         conv.decimal("TotalRetencionesIVA", t.TotalRetencionesIVA, amountScale),
+			TotalRetencionesISR:         conv.decimal("TotalRetencionesISR", t.TotalRetencionesISR, amountScale),
+			TotalRetencionesIEPS:        conv.decimal("TotalRetencionesIEPS", t.TotalRetencionesIEPS, amountScale),
+			TotalTrasladosBaseIVA16:     conv.decimal("TotalTrasladosBaseIVA16", t.TotalTrasladosBaseIVA16, amountScale),
+			TotalTrasladosImpuestoIVA16: conv.decimal("TotalTrasladosImpuestoIVA16", t.TotalTrasladosImpuestoIVA16, amountScale),
+			TotalTrasladosBaseIVA8:      conv.decimal("TotalTrasladosBaseIVA8", t.TotalTrasladosBaseIVA8, amountScale),
+			TotalTrasladosImpuestoIVA8:  conv.decimal("TotalTrasladosImpuestoIVA8", t.TotalTrasladosImpuestoIVA8, amountScale),
+			TotalTrasladosBaseIVA0:      conv.decimal("TotalTrasladosBaseIVA0", t.TotalTrasladosBaseIVA0, amountScale),
+			TotalTrasladosImpuestoIVA0:  conv.decimal("TotalTrasladosImpuestoIVA0", t.TotalTrasladosImpuestoIVA0, amountScale),
+			TotalTrasladosBaseIVAExento: conv.decimal("TotalTrasladosBaseIVAExento", t.TotalTrasladosBaseIVAExento, amountScale),
+			MontoTotalPagos:             conv.decimal("MontoTotalPagos", t.MontoTotalPagos, amountScale),
+		},
+	}
+
+	for _, pago := range pagos.Pagos {
+		pp := ParquetPago20{
+			FechaPago:       pago.FechaPago,
+			FormaDePagoP:    pago.FormaDePagoP,
+			MonedaP:         pago.MonedaP,
+			TipoCambioP:     conv.decimal("TipoCambioP", pago.TipoCambioP, amountScale),
+			Monto:           conv.decimal("Monto", pago.Monto, amountScale),
+			NumOperacion:    pago.NumOperacion,
+			RfcEmisorCtaOrd: pago.RfcEmisorCtaOrd,
+			NomBancoOrdExt:  pago.NomBancoOrdExt,
+			CtaOrdenante:    pago.CtaOrdenante,
+			RfcEmisorCtaBen: pago.RfcEmisorCtaBen,
+			CtaBeneficiario: pago.CtaBeneficiario,
+			TipoCadPago:     pago.TipoCadPago,
+		}
+		for _, impuestos := range pago.ImpuestosP {
+			for _, tr := range impuestos.TrasladosP {
+				pp.TrasladosP = append(pp.TrasladosP, ParquetTrasladoP{
+					Base:       conv.decimal("BaseP", tr.BaseP, amountScale),
+					Impuesto:   tr.ImpuestoP,
+					TipoFactor: tr.TipoFactorP,
+					TasaOCuota: conv.decimal("TasaOCuotaP", tr.TasaOCuotaP, amountScale),
+					Importe:    conv.decimal("ImporteP", tr.ImporteP, amountScale),
+				})
+			}
+			for _, ret := range impuestos.RetencionesP {
+				pp.RetencionesP = append(pp.RetencionesP, ParquetRetencion{Impuesto: ret.ImpuestoP, Importe: conv.decimal("ImporteP", ret.ImporteP, amountScale)})
+			}
+		}
+		for _, docto := range pago.DoctoRelacionado {
+			pd := ParquetDoctoRelacionado20{
+				IdDocumento:      docto.IdDocumento,
+				Serie:            docto.Serie,
+				Folio:            docto.Folio,
+				MonedaDR:         docto.MonedaDR,
+				EquivalenciaDR:   conv.decimal("EquivalenciaDR", docto.EquivalenciaDR, equivalenciaScale),
+				NumParcialidad:   docto.NumParcialidad,
+				ImpSaldoAnt:      conv.decimal("ImpSaldoAnt", docto.ImpSaldoAnt, amountScale),
+				ImpPagado:        conv.decimal("ImpPagado", docto.ImpPagado, amountScale),
+				ImpSaldoInsoluto: conv.decimal("ImpSaldoInsoluto", docto.ImpSaldoInsoluto, amountScale),
+				ObjetoImpDR:      docto.ObjetoImpDR,
+			}
+			for _, impuestos := range docto.ImpuestosDR {
+				for _, tr := range impuestos.TrasladosDR {
+					pd.TrasladosDR = append(pd.TrasladosDR, toParquetImpuestoDR(conv, tr))
+				}
+				for _, ret := range impuestos.RetencionesDR {
+					pd.RetencionesDR = append(pd.RetencionesDR, toParquetImpuestoDR(conv, ret))
+				}
+			}
+			pp.DoctoRelacionado = append(pp.DoctoRelacionado, pd)
+		}
+		row.Pagos = append(row.Pagos, pp)
+	}
+
+	return row
+}
+
+func toParquetNomina12(conv *decimalConverter, nomina models.Nomina12Data) ParquetNomina12 {
+	r := nomina.Receptor
+	row := ParquetNomina12{
+		Version:           nomina.Version,
+		TipoNomina:        nomina.TipoNomina,
+		FechaPago:         nomina.FechaPago,
+		FechaInicialPago:  nomina.FechaInicialPago,
+		FechaFinalPago:    nomina.FechaFinalPago,
+		NumDiasPagados:    conv.decimal("NumDiasPagados", nomina.NumDiasPagados, daysScale),
+		TotalPercepciones: conv.decimal("TotalPercepciones", nomina.TotalPercepciones, amountScale),
+		TotalDeducciones:  conv.decimal("TotalDeducciones", nomina.TotalDeducciones, amountScale),
+		TotalOtrosPagos:   conv.decimal("TotalOtrosPagos", nomina.TotalOtrosPagos, amountScale),
+		RegistroPatronal:  nomina.Emisor.RegistroPatronal,
+		Receptor: ParquetNomina12Receptor{
+			Curp:                   r.Curp,
+			NumSeguridadSocial:     r.NumSeguridadSocial,
+			FechaInicioRelLaboral:  r.FechaInicioRelLaboral,
+			TipoContrato:           r.TipoContrato,
+			TipoRegimen:            r.TipoRegimen,
+			NumEmpleado:            r.NumEmpleado,
+			Departamento:           r.Departamento,
+			Puesto:                 r.Puesto,
+			PeriodicidadPago:       r.PeriodicidadPago,
+			SalarioBaseCotApor:     conv.decimal("SalarioBaseCotApor", r.SalarioBaseCotApor, amountScale),
+			SalarioDiarioIntegrado: conv.decimal("SalarioDiarioIntegrado", r.SalarioDiarioIntegrado, amountScale),
+			ClaveEntFed:            r.ClaveEntFed,
+		},
+	}
+
+	for _, p := range nomina.Percepciones.Percepcion {
+		row.Percepciones = append(row.Percepciones, ParquetNomina12Concepto{
+			Tipo:           p.TipoPercepcion,
+			Clave:          p.Clave,
+			Concepto:       p.Concepto,
+			ImporteGravado: conv.decimal("ImporteGravado", p.ImporteGravado, amountScale),
+			ImporteExento:  conv.decimal("ImporteExento", p.ImporteExento, amountScale),
+		})
+	}
+	for _, d := range nomina.Deducciones.Deduccion {
+		row.Deducciones = append(row.Deducciones, ParquetNomina12Concepto{
+			Tipo:     d.TipoDeduccion,
+			Clave:    d.Clave,
+			Concepto: d.Concepto,
+			Importe:  conv.decimal("Importe", d.Importe, amountScale),
+		})
+	}
+	for _, o := range nomina.OtrosPagos.OtroPago {
+		row.OtrosPagos = append(row.OtrosPagos, ParquetNomina12Concepto{
+			Tipo:     o.TipoOtroPago,
+			Clave:    o.Clave,
+			Concepto: o.Concepto,
+			Importe:  conv.decimal("Importe", o.Importe, amountScale),
+		})
+	}
+
+	return row
+}
+
+func toParquetTraslado(conv *decimalConverter, base, impuesto, tipoFactor, tasaOCuota, importe string) ParquetTraslado {
+	return ParquetTraslado{
+		Base:       conv.decimal("Base", base, amountScale),
+		Impuesto:   impuesto,
+		TipoFactor: tipoFactor,
+		TasaOCuota: conv.decimal("TasaOCuota", tasaOCuota, amountScale),
+		Importe:    conv.decimal("Importe", importe, amountScale),
+	}
+}
+
+func toParquetImpuestoDR(conv *decimalConverter, item models.ImpuestoDRItem) ParquetTrasladoP {
+	return ParquetTrasladoP{
+		Base:       conv.decimal("BaseDR", item.BaseDR, amountScale),
+		Impuesto:   item.ImpuestoDR,
+		TipoFactor: item.TipoFactorDR,
+		TasaOCuota: conv.decimal("TasaOCuotaDR", item.TasaOCuotaDR, amountScale),
+		Importe:    conv.decimal("ImporteDR", item.ImporteDR, amountScale),
+	}
+}
+
+func firstUUID(doc models.CFDI40Data) string {
+	if len(doc.TFD11) > 0 {
+		return doc.TFD11[0].UUID
+	}
+	return ""
+}
+
+// decimalPrecision es la precision de todas las columnas decimales del esquema.
+const decimalPrecision = 18
+
+var (
+	// ErrInvalidDecimal indicates a value that is not a decimal number.
+	ErrInvalidDecimal = errors.New("not a decimal number")
+	// ErrDecimalScale indicates a value with more decimal places than its column.
+	ErrDecimalScale = errors.New("more decimal places than the column scale")
+	// ErrDecimalRange indicates a value with more digits than the column precision.
+	ErrDecimalRange = errors.New("out of the column precision")
+
+	maxUnscaled = decimal.New(1, decimalPrecision).Sub(decimal.New(1, 0))
+)
+
+// DecimalError reports a value that cannot be stored exactly in its decimal column.
+type DecimalError struct {
+	Field string
+	Value string
+	Scale int32
+	Err   error
+}
+
+func (e *DecimalError) Error() string {
+	return fmt.Sprintf("%s %q as decimal(%d:%d): %v", e.Field, e.Value, e.Scale, decimalPrecision, e.Err)
+}
+
+func (e *DecimalError) Unwrap() error {
+	return e.Err
+}
+
+// decimalConverter converts decimal strings into the unscaled values of the decimal columns,
+// collecting the values that cannot be stored exactly instead of rounding or dropping them.
+type decimalConverter struct {
+	errs []error
+}
+
+// decimal returns the unscaled value of val for the given scale, or nil when val is empty.
+func (conv *decimalConverter) decimal(field, val string, scale int32) *int64 {
+	if val == "" {
+		return nil
+	}
+	d, err := decimal.NewFromString(val)
+	if err != nil {
+		conv.errs = append(conv.errs, &DecimalError{Field: field, Value: val, Scale: scale, Err: ErrInvalidDecimal})
+		return nil
+	}
+
+	shifted := d.Shift(scale)
+	if !shifted.Equal(shifted.Truncate(0)) {
+		conv.errs = append(conv.errs, &DecimalError{Field: field, Value: val, Scale: scale, Err: ErrDecimalScale})
+		return nil
+	}
+	if shifted.Abs().GreaterThan(maxUnscaled) {
+		conv.errs = append(conv.errs, &DecimalError{Field: field, Value: val, Scale: scale, Err: ErrDecimalRange})
+		return nil
+	}
+	unscaled := shifted.IntPart()
+	return &unscaled
+}
+
+func (conv *decimalConverter) err() error {
+	return errors.Join(conv.errs...)
+}
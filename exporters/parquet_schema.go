@@ -0,0 +1,228 @@
+package exporters
+
+// Las estructuras de este archivo definen el esquema Parquet de un CFDI 4.0.
+// Reflejan los modelos de models con los importes tipados como decimales;
+// los conceptos y los complementos que pueden repetirse son grupos repetidos
+// y el TFD es una estructura opcional.
+
+// ParquetDocument es la fila Parquet de un CFDI 4.0 con sus complementos.
+type ParquetDocument struct {
+	Version           string                    `parquet:"version"`
+	Serie             string                    `parquet:"serie"`
+	Folio             string                    `parquet:"folio"`
+	Fecha             string                    `parquet:"fecha"`
+	NoCertificado     string                    `parquet:"no_certificado"`
+	SubTotal          *int64                    `parquet:"subtotal,optional,decimal(6:18)"`
+	Descuento         *int64                    `parquet:"descuento,optional,decimal(6:18)"`
+	Total             *int64                    `parquet:"total,optional,decimal(6:18)"`
+	Moneda            string                    `parquet:"moneda"`
+	TipoCambio        *int64                    `parquet:"tipo_cambio,optional,decimal(6:18)"`
+	TipoComprobante   string                    `parquet:"tipo_comprobante"`
+	MetodoPago        string                    `parquet:"metodo_pago"`
+	FormaPago         string                    `parquet:"forma_pago"`
+	CondicionesPago   string                    `parquet:"condiciones_pago"`
+	LugarExpedicion   string                    `parquet:"lugar_expedicion"`
+	Exportacion       string                    `parquet:"exportacion"`
+	Confirmacion      string                    `parquet:"confirmacion"`
+	Emisor            ParquetEmisor             `parquet:"emisor"`
+	Receptor          ParquetReceptor           `parquet:"receptor"`
+	Conceptos         []ParquetConcepto         `parquet:"conceptos,list"`
+	Impuestos         ParquetImpuestos          `parquet:"impuestos"`
+	CFDIsRelacionados []ParquetCFDIRelacionado  `parquet:"cfdis_relacionados,list"`
+	Complementos      string                    `parquet:"complementos"`
+	Addendas          string                    `parquet:"addendas"`
+	TFD11             *ParquetTFD11             `parquet:"tfd11,optional"`
+	Pagos20           []ParquetPagos20          `parquet:"pagos20,list"`
+	Nomina12          []ParquetNomina12         `parquet:"nomina12,list"`
+	VentaVehiculos11  []ParquetVentaVehiculos11 `parquet:"venta_vehiculos11,list"`
+}
+
+// ParquetEmisor es el emisor del CFDI 4.0.
+type ParquetEmisor struct {
+	RFC              string `parquet:"rfc"`
+	Nombre           string `parquet:"nombre"`
+	RegimenFiscal    string `parquet:"regimen_fiscal"`
+	FacAtrAdquirente string `parquet:"fac_atr_adquirente"`
+}
+
+// ParquetReceptor es el receptor del CFDI 4.0.
+type ParquetReceptor struct {
+	RFC                     string `parquet:"rfc"`
+	Nombre                  string `parquet:"nombre"`
+	DomicilioFiscalReceptor string `parquet:"domicilio_fiscal_receptor"`
+	ResidenciaFiscal        string `parquet:"residencia_fiscal"`
+	NumRegIdTrib            string `parquet:"num_reg_id_trib"`
+	RegimenFiscalReceptor   string `parquet:"regimen_fiscal_receptor"`
+	UsoCFDI                 string `parquet:"uso_cfdi"`
+}
+
+// ParquetConcepto es un concepto del CFDI 4.0.
+type ParquetConcepto struct {
+	ClaveProdServ    string             `parquet:"clave_prod_serv"`
+	NoIdentificacion string             `parquet:"no_identificacion"`
+	Cantidad         *int64             `parquet:"cantidad,optional,decimal(6:18)"`
+	ClaveUnidad      string             `parquet:"clave_unidad"`
+	Unidad           string             `parquet:"unidad"`
+	Descripcion      string             `parquet:"descripcion"`
+	ValorUnitario    *int64             `parquet:"valor_unitario,optional,decimal(6:18)"`
+	Importe          *int64             `parquet:"importe,optional,decimal(6:18)"`
+	Descuento        *int64             `parquet:"descuento,optional,decimal(6:18)"`
+	ObjetoImp        string             `parquet:"objeto_imp"`
+	Traslados        []ParquetTraslado  `parquet:"traslados,list"`
+	Retenciones      []ParquetRetencion `parquet:"retenciones,list"`
+}
+
+// ParquetImpuestos son los impuestos globales del CFDI 4.0.
+type ParquetImpuestos struct {
+	TotalImpuestosTrasladados *int64             `parquet:"total_impuestos_trasladados,optional,decimal(6:18)"`
+	TotalImpuestosRetenidos   *int64             `parquet:"total_impuestos_retenidos,optional,decimal(6:18)"`
+	Traslados                 []ParquetTraslado  `parquet:"traslados,list"`
+	Retenciones               []ParquetRetencion `parquet:"retenciones,list"`
+}
+
+// ParquetTraslado es un traslado de impuesto, global o de un concepto.
+type ParquetTraslado struct {
+	Base       *int64 `parquet:"base,optional,decimal(6:18)"`
+	Impuesto   string `parquet:"impuesto"`
+	TipoFactor string `parquet:"tipo_factor"`
+	TasaOCuota *int64 `parquet:"tasa_o_cuota,optional,decimal(6:18)"`
+	Importe    *int64 `parquet:"importe,optional,decimal(6:18)"`
+}
+
+// ParquetRetencion es una retención de impuesto, global o de un concepto.
+type ParquetRetencion struct {
+	Impuesto string `parquet:"impuesto"`
+	Importe  *int64 `parquet:"importe,optional,decimal(6:18)"`
+}
+
+// ParquetCFDIRelacionado es un CFDI relacionado.
+type ParquetCFDIRelacionado struct {
+	UUID         string `parquet:"uuid"`
+	TipoRelacion string `parquet:"tipo_relacion"`
+}
+
+// ParquetTFD11 es el Timbre Fiscal Digital 1.1.
+type ParquetTFD11 struct {
+	Version          string `parquet:"version"`
+	NoCertificadoSAT string `parquet:"no_certificado_sat"`
+	UUID             string `parquet:"uuid"`
+	FechaTimbrado    string `parquet:"fecha_timbrado"`
+	RfcProvCert      string `parquet:"rfc_prov_cert"`
+}
+
+// ParquetPagos20 es el complemento Pagos 2.0.
+type ParquetPagos20 struct {
+	Version string           `parquet:"version"`
+	Totales ParquetTotales20 `parquet:"totales"`
+	Pagos   []ParquetPago20  `parquet:"pagos,list"`
+}
+
+// ParquetTotales20 son los totales del complemento Pagos 2.0.
+type ParquetTotales20 struct {
+	TotalRetencionesIVA         *int64 `parquet:"total_retenciones_iva,optional,decimal(6:18)"`
+	TotalRetencionesISR         *int64 `parquet:"total_retenciones_isr,optional,decimal(6:18)"`
+	TotalRetencionesIEPS        *int64 `parquet:"total_retenciones_ieps,optional,decimal(6:18)"`
+	TotalTrasladosBaseIVA16     *int64 `parquet:"total_traslados_base_iva_16,optional,decimal(6:18)"`
+	TotalTrasladosImpuestoIVA16 *int64 `parquet:"total_traslados_impuesto_iva_16,optional,decimal(6:18)"`
+	TotalTrasladosBaseIVA8      *int64 `parquet:"total_traslados_base_iva_8,optional,decimal(6:18)"`
+	TotalTrasladosImpuestoIVA8  *int64 `parquet:"total_traslados_impuesto_iva_8,optional,decimal(6:18)"`
+	TotalTrasladosBaseIVA0      *int64 `parquet:"total_traslados_base_iva_0,optional,decimal(6:18)"`
+	TotalTrasladosImpuestoIVA0  *int64 `parquet:"total_traslados_impuesto_iva_0,optional,decimal(6:18)"`
+	TotalTrasladosBaseIVAExento *int64 `parquet:"total_traslados_base_iva_exento,optional,decimal(6:18)"`
+	MontoTotalPagos             *int64 `parquet:"monto_total_pagos,optional,decimal(6:18)"`
+}
+
+// ParquetPago20 es un pago individual del complemento Pagos 2.0.
+type ParquetPago20 struct {
+	FechaPago        string                      `parquet:"fecha_pago"`
+	FormaDePagoP     string                      `parquet:"forma_de_pago_p"`
+	MonedaP          string                      `parquet:"moneda_p"`
+	TipoCambioP      *int64                      `parquet:"tipo_cambio_p,optional,decimal(6:18)"`
+	Monto            *int64                      `parquet:"monto,optional,decimal(6:18)"`
+	NumOperacion     string                      `parquet:"num_operacion"`
+	RfcEmisorCtaOrd  string                      `parquet:"rfc_emisor_cta_ord"`
+	NomBancoOrdExt   string                      `parquet:"nom_banco_ord_ext"`
+	CtaOrdenante     string                      `parquet:"cta_ordenante"`
+	RfcEmisorCtaBen  string                      `parquet:"rfc_emisor_cta_ben"`
+	CtaBeneficiario  string                      `parquet:"cta_beneficiario"`
+	TipoCadPago      string                      `parquet:"tipo_cad_pago"`
+	DoctoRelacionado []ParquetDoctoRelacionado20 `parquet:"docto_relacionado,list"`
+	TrasladosP       []ParquetTrasladoP          `parquet:"traslados_p,list"`
+	RetencionesP     []ParquetRetencion          `parquet:"retenciones_p,list"`
+}
+
+// ParquetDoctoRelacionado20 es un documento relacionado de un pago.
+type ParquetDoctoRelacionado20 struct {
+	IdDocumento      string             `parquet:"id_documento"`
+	Serie            string             `parquet:"serie"`
+	Folio            string             `parquet:"folio"`
+	MonedaDR         string             `parquet:"moneda_dr"`
+	EquivalenciaDR   *int64             `parquet:"equivalencia_dr,optional,decimal(10:18)"`
+	NumParcialidad   string             `parquet:"num_parcialidad"`
+	ImpSaldoAnt      *int64             `parquet:"imp_saldo_ant,optional,decimal(6:18)"`
+	ImpPagado        *int64             `parquet:"imp_pagado,optional,decimal(6:18)"`
+	ImpSaldoInsoluto *int64             `parquet:"imp_saldo_insoluto,optional,decimal(6:18)"`
+	ObjetoImpDR      string             `parquet:"objeto_imp_dr"`
+	TrasladosDR      []ParquetTrasladoP `parquet:"traslados_dr,list"`
+	RetencionesDR    []ParquetTrasladoP `parquet:"retenciones_dr,list"`
+}
+
+// ParquetTrasladoP es un impuesto de un pago o de un documento relacionado.
+type ParquetTrasladoP struct {
+	Base       *int64 `parquet:"base,optional,decimal(6:18)"`
+	Impuesto   string `parquet:"impuesto"`
+	TipoFactor string `parquet:"tipo_factor"`
+	TasaOCuota *int64 `parquet:"tasa_o_cuota,optional,decimal(6:18)"`
+	Importe    *int64 `parquet:"importe,optional,decimal(6:18)"`
+}
+
+// ParquetNomina12 es el complemento Nómina 1.2.
+type ParquetNomina12 struct {
+	Version           string                    `parquet:"version"`
+	TipoNomina        string                    `parquet:"tipo_nomina"`
+	FechaPago         string                    `parquet:"fecha_pago"`
+	FechaInicialPago  string                    `parquet:"fecha_inicial_pago"`
+	FechaFinalPago    string                    `parquet:"fecha_final_pago"`
+	NumDiasPagados    *int64                    `parquet:"num_dias_pagados,optional,decimal(3:18)"`
+	TotalPercepciones *int64                    `parquet:"total_percepciones,optional,decimal(6:18)"`
+	TotalDeducciones  *int64                    `parquet:"total_deducciones,optional,decimal(6:18)"`
+	TotalOtrosPagos   *int64                    `parquet:"total_otros_pagos,optional,decimal(6:18)"`
+	RegistroPatronal  string                    `parquet:"registro_patronal"`
+	Receptor          ParquetNomina12Receptor   `parquet:"receptor"`
+	Percepciones      []ParquetNomina12Concepto `parquet:"percepciones,list"`
+	Deducciones       []ParquetNomina12Concepto `parquet:"deducciones,list"`
+	OtrosPagos        []ParquetNomina12Concepto `parquet:"otros_pagos,list"`
+}
+
+// ParquetNomina12Receptor es el receptor del complemento Nómina 1.2.
+type ParquetNomina12Receptor struct {
+	Curp                   string `parquet:"curp"`
+	NumSeguridadSocial     string `parquet:"num_seguridad_social"`
+	FechaInicioRelLaboral  string `parquet:"fecha_inicio_rel_laboral"`
+	TipoContrato           string `parquet:"tipo_contrato"`
+	TipoRegimen            string `parquet:"tipo_regimen"`
+	NumEmpleado            string `parquet:"num_empleado"`
+	Departamento           string `parquet:"departamento"`
+	Puesto                 string `parquet:"puesto"`
+	PeriodicidadPago       string `parquet:"periodicidad_pago"`
+	SalarioBaseCotApor     *int64 `parquet:"salario_base_cot_apor,optional,decimal(6:18)"`
+	SalarioDiarioIntegrado *int64 `parquet:"salario_diario_integrado,optional,decimal(6:18)"`
+	ClaveEntFed            string `parquet:"clave_ent_fed"`
+}
+
+// ParquetNomina12Concepto es una percepción, deducción u otro pago del complemento Nómina 1.2.
+type ParquetNomina12Concepto struct {
+	Tipo           string `parquet:"tipo"`
+	Clave          string `parquet:"clave"`
+	Concepto       string `parquet:"concepto"`
+	ImporteGravado *int64 `parquet:"importe_gravado,optional,decimal(6:18)"`
+	ImporteExento  *int64 `parquet:"importe_exento,optional,decimal(6:18)"`
+	Importe        *int64 `parquet:"importe,optional,decimal(6:18)"`
+}
+
+// ParquetVentaVehiculos11 es el complemento Venta Vehículos 1.1.
+type ParquetVentaVehiculos11 struct {
+	Version        string `parquet:"version"`
+	ClaveVehicular string `parquet:"clave_vehicular"`
+	Niv            string `parquet:"niv"`
+}
@@ -0,0 +1,94 @@
+package exporters_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/exporters"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestWriteParquet(t *testing.T) {
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseConcepts().UseConceptsWithTaxes().UsePagos20().UseNomina12()
+
+	var docs []models.CFDI40Data
+	for _, path := range []string{"../../test/recursos/cfdi40_pagos.xml", "../../test/recursos/nomina12.xml"} {
+		data, err := handler.TransformFromFile(path)
+		require.NoError(t, err)
+		docs = append(docs, *data)
+	}
+
+	var buf bytes.Buffer
+	pw := exporters.NewParquetWriter(&buf)
+	require.NoError(t, pw.Write(docs[:1]))
+	require.NoError(t, pw.Write(docs[1:]))
+	require.NoError(t, pw.Close())
+
+	rows, err := parquet.Read[exporters.ParquetDocument](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	pagos := rows[0]
+	require.NotNil(t, pagos.TFD11)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", pagos.TFD11.UUID)
+	require.Len(t, pagos.Conceptos, 1)
+	require.Len(t, pagos.Pagos20, 1)
+	require.Len(t, pagos.Pagos20[0].Pagos, 1)
+
+	pago := pagos.Pagos20[0].Pagos[0]
+	require.NotNil(t, pago.Monto)
+	assert.Equal(t, int64(1160000000), *pago.Monto)
+	require.Len(t, pago.DoctoRelacionado, 1)
+	require.NotNil(t, pago.DoctoRelacionado[0].EquivalenciaDR)
+	assert.Equal(t, int64(10000000000), *pago.DoctoRelacionado[0].EquivalenciaDR)
+	require.Len(t, pago.TrasladosP, 1)
+	assert.Equal(t, int64(160000), *pago.TrasladosP[0].TasaOCuota)
+
+	nomina := rows[1]
+	assert.Nil(t, nomina.TFD11)
+	assert.Nil(t, nomina.Impuestos.TotalImpuestosTrasladados)
+	require.Len(t, nomina.Nomina12, 1)
+	assert.Len(t, nomina.Nomina12[0].Percepciones, 2)
+	assert.Equal(t, "OAAJ840102HJCVRN00", nomina.Nomina12[0].Receptor.Curp)
+}
+
+func TestToParquetDocumentDecimalErrors(t *testing.T) {
+	doc := models.CFDI40Data{TFD11: []models.TFD11{{UUID: "11111111-1111-1111-1111-111111111111"}}}
+	doc.CFDI40.SubTotal = "100.1234567"
+	doc.CFDI40.Total = "10000000000000"
+	doc.CFDI40.TipoCambio = "N/A"
+
+	row, err := exporters.ToParquetDocument(doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "11111111-1111-1111-1111-111111111111")
+	assert.ErrorIs(t, err, exporters.ErrDecimalScale)
+	assert.ErrorIs(t, err, exporters.ErrDecimalRange)
+	assert.ErrorIs(t, err, exporters.ErrInvalidDecimal)
+
+	var decimalErr *exporters.DecimalError
+	require.ErrorAs(t, err, &decimalErr)
+	assert.Equal(t, "SubTotal", decimalErr.Field)
+	assert.Nil(t, row.SubTotal)
+	assert.Nil(t, row.Descuento)
+
+	t.Run("Write no escribe el lote", func(t *testing.T) {
+		var buf bytes.Buffer
+		pw := exporters.NewParquetWriter(&buf)
+		assert.ErrorIs(t, pw.Write([]models.CFDI40Data{doc}), exporters.ErrDecimalScale)
+	})
+
+	t.Run("Valores exactos dentro de la precision", func(t *testing.T) {
+		doc.CFDI40.SubTotal = "100.123456"
+		doc.CFDI40.Total = "999999999999.999999"
+		doc.CFDI40.TipoCambio = "1"
+
+		row, err := exporters.ToParquetDocument(doc)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100123456), *row.SubTotal)
+		assert.Equal(t, int64(999999999999999999), *row.Total)
+	})
+}
@@ -0,0 +1,60 @@
+package exporters_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/exporters"
+	"github.com/sucksens/gocfdi-transform/formatters"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+	"github.com/xuri/excelize/v2"
+)
+
+func parseFiles(t *testing.T, paths ...string) []models.CFDI40Data {
+	t.Helper()
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseConcepts().UseConceptsWithTaxes().UsePagos20().UseNomina12()
+
+	docs := make([]models.CFDI40Data, 0, len(paths))
+	for _, path := range paths {
+		data, err := handler.TransformFromFile(path)
+		require.NoError(t, err)
+		docs = append(docs, *data)
+	}
+	return docs
+}
+
+func TestWriteXLSX(t *testing.T) {
+	docs := parseFiles(t, "../../test/recursos/cfdi40_pagos.xml", "../../test/recursos/nomina12.xml")
+	tables := formatters.NewCFDI40Formatter(formatters.NewDefaultConfig()).Tables(docs)
+
+	var buf bytes.Buffer
+	require.NoError(t, exporters.WriteXLSX(&buf, tables))
+
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.Equal(t, []string{"Comprobantes", "Conceptos", "Pagos", "DoctosRelacionados", "Nómina"}, file.GetSheetList())
+
+	rows, err := file.GetRows("Pagos")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "uuid", rows[0][0])
+
+	// Las columnas decimales se escriben como numeros y las de texto como cadenas
+	montoCell, err := excelize.CoordinatesToCellName(11, 2)
+	require.NoError(t, err)
+	montoType, err := file.GetCellType("Pagos", montoCell)
+	require.NoError(t, err)
+	assert.Equal(t, excelize.CellTypeUnset, montoType)
+	monto, err := file.GetCellValue("Pagos", montoCell)
+	require.NoError(t, err)
+	assert.Equal(t, "1160", monto)
+
+	uuidType, err := file.GetCellType("Pagos", "A2")
+	require.NoError(t, err)
+	assert.NotEqual(t, excelize.CellTypeUnset, uuidType)
+}
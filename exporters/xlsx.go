@@ -0,0 +1,102 @@
+package exporters
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/formatters"
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetNames contiene el nombre de la hoja de calculo para cada tabla.
+var sheetNames = map[string]string{
+	formatters.TableComprobantes:       "Comprobantes",
+	formatters.TableConceptos:          "Conceptos",
+	formatters.TablePagos:              "Pagos",
+	formatters.TableDoctosRelacionados: "DoctosRelacionados",
+	formatters.TableNomina:             "Nómina",
+}
+
+// WriteXLSX writes the tables as an Excel workbook, one sheet per table, to the given writer.
+// Decimal columns are written as numbers; values that cannot be parsed are written as text.
+func WriteXLSX(w io.Writer, tables []*formatters.Table) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	defaultSheet := file.GetSheetName(0)
+	for i, table := range tables {
+		sheet := sheetName(table)
+		if i == 0 {
+			if err := file.SetSheetName(defaultSheet, sheet); err != nil {
+				return fmt.Errorf("error creating sheet %s: %w", sheet, err)
+			}
+		} else if _, err := file.NewSheet(sheet); err != nil {
+			return fmt.Errorf("error creating sheet %s: %w", sheet, err)
+		}
+
+		if err := writeSheet(file, sheet, table); err != nil {
+			return err
+		}
+	}
+
+	if err := file.Write(w); err != nil {
+		return fmt.Errorf("error writing XLSX: %w", err)
+	}
+	return nil
+}
+
+func writeSheet(file *excelize.File, sheet string, table *formatters.Table) error {
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return fmt.Errorf("error creating sheet %s: %w", sheet, err)
+	}
+
+	headers := table.Headers()
+	header := make([]interface{}, len(headers))
+	for i, h := range headers {
+		header[i] = h
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return fmt.Errorf("error writing sheet %s: %w", sheet, err)
+	}
+
+	for r, row := range table.Rows {
+		values := make([]interface{}, len(row))
+		for i, val := range row {
+			values[i] = cellValue(table.Columns[i], val)
+		}
+		cell, err := excelize.CoordinatesToCellName(1, r+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, values); err != nil {
+			return fmt.Errorf("error writing sheet %s: %w", sheet, err)
+		}
+	}
+
+	if err := stream.Flush(); err != nil {
+		return fmt.Errorf("error writing sheet %s: %w", sheet, err)
+	}
+	return nil
+}
+
+// cellValue returns the typed value of a cell, a float64 for decimal columns when possible.
+func cellValue(col formatters.Column, val string) interface{} {
+	if col.Type != formatters.ColumnDecimal {
+		return val
+	}
+	d, err := decimal.NewFromString(val)
+	if err != nil {
+		return val
+	}
+	f, _ := d.Float64()
+	return f
+}
+
+func sheetName(table *formatters.Table) string {
+	if name, ok := sheetNames[table.Name]; ok {
+		return name
+	}
+	return table.Name
+}
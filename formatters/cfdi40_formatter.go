@@ -0,0 +1,360 @@
+package formatters
+
+import (
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// Claves de impuestos del catalogo c_Impuesto del SAT.
+const (
+	impuestoISR  = "001"
+	impuestoIVA  = "002"
+	impuestoIEPS = "003"
+)
+
+var comprobantesColumns = []Column{
+	str("uuid"), str("version"), str("serie"), str("folio"), str("fecha"), str("no_certificado"),
+	dec("subtotal"), dec("descuento"), dec("total"), str("moneda"), dec("tipo_cambio"),
+	str("tipo_comprobante"), str("metodo_pago"), str("forma_pago"), str("condiciones_pago"),
+	str("lugar_expedicion"), str("exportacion"), str("confirmacion"),
+	str("emisor_rfc"), str("emisor_nombre"), str("emisor_regimen_fiscal"), str("emisor_fac_atr_adquirente"),
+	str("receptor_rfc"), str("receptor_nombre"), str("receptor_domicilio_fiscal"), str("receptor_residencia_fiscal"),
+	str("receptor_num_reg_id_trib"), str("receptor_regimen_fiscal"), str("receptor_uso_cfdi"),
+	dec("total_impuestos_trasladados"), dec("total_impuestos_retenidos"),
+	dec("iva_trasladado"), dec("ieps_trasladado"), dec("isr_retenido"), dec("iva_retenido"), dec("ieps_retenido"),
+	str("cfdis_relacionados"), str("complementos"), str("addendas"),
+	str("fecha_timbrado"), str("rfc_prov_cert"), str("no_certificado_sat"),
+}
+
+var conceptosColumns = []Column{
+	str("uuid"), str("serie"), str("folio"), str("fecha"), str("emisor_rfc"), str("receptor_rfc"),
+	str("clave_prod_serv"), str("no_identificacion"), dec("cantidad"), str("clave_unidad"), str("unidad"),
+	str("descripcion"), dec("valor_unitario"), dec("importe"), dec("descuento"), str("objeto_imp"),
+	dec("iva_trasladado"), dec("ieps_trasladado"), dec("isr_retenido"), dec("iva_retenido"), dec("ieps_retenido"),
+}
+
+var pagosColumns = []Column{
+	str("uuid"), str("serie"), str("folio"), str("emisor_rfc"), str("receptor_rfc"), str("version_pagos"),
+	str("fecha_pago"), str("forma_de_pago_p"), str("moneda_p"), dec("tipo_cambio_p"), dec("monto"),
+	str("num_operacion"), str("rfc_emisor_cta_ord"), str("nom_banco_ord_ext"), str("cta_ordenante"),
+	str("rfc_emisor_cta_ben"), str("cta_beneficiario"), str("tipo_cad_pago"),
+	dec("iva_trasladado_p"), dec("ieps_trasladado_p"), dec("isr_retenido_p"), dec("iva_retenido_p"), dec("ieps_retenido_p"),
+}
+
+var doctosRelacionadosColumns = []Column{
+	str("uuid"), str("emisor_rfc"), str("receptor_rfc"), str("fecha_pago"), str("moneda_p"), dec("tipo_cambio_p"),
+	str("id_documento"), str("serie_dr"), str("folio_dr"), str("moneda_dr"), dec("equivalencia_dr"),
+	str("num_parcialidad"), dec("imp_saldo_ant"), dec("imp_pagado"), dec("imp_saldo_insoluto"), str("objeto_imp_dr"),
+}
+
+var nominaColumns = []Column{
+	str("uuid"), str("emisor_rfc"), str("receptor_rfc"), str("curp"), str("num_empleado"),
+	str("tipo_nomina"), str("fecha_pago"), str("fecha_inicial_pago"), str("fecha_final_pago"), dec("num_dias_pagados"),
+	str("movimiento"), str("tipo"), str("clave"), str("concepto"),
+	dec("importe_gravado"), dec("importe_exento"), dec("importe"),
+}
+
+// CFDI40Formatter convierte CFDI40Data en tablas planas.
+type CFDI40Formatter struct {
+	config Config
+}
+
+// NewCFDI40Formatter creates a new CFDI40Formatter with the given configuration.
+func NewCFDI40Formatter(cfg Config) *CFDI40Formatter {
+	return &CFDI40Formatter{config: cfg}
+}
+
+// Tables returns every table for the given documents, in a stable order.
+func (f *CFDI40Formatter) Tables(docs []models.CFDI40Data) []*Table {
+	return []*Table{
+		f.Comprobantes(docs),
+		f.Conceptos(docs),
+		f.Pagos(docs),
+		f.DoctosRelacionados(docs),
+		f.Nomina(docs),
+	}
+}
+
+// Comprobantes returns one row per document.
+func (f *CFDI40Formatter) Comprobantes(docs []models.CFDI40Data) *Table {
+	table := newTable(TableComprobantes, comprobantesColumns, f.config)
+
+	for _, doc := range docs {
+		c := doc.CFDI40
+		tfd := firstTFD(doc)
+
+		var trasladados, retenidos = map[string]string{}, map[string]string{}
+		for _, tr := range c.Impuestos.Traslados {
+			trasladados[tr.Impuesto] = helpers.SumStrings(trasladados[tr.Impuesto], tr.Importe)
+		}
+		for _, ret := range c.Impuestos.Retenciones {
+			retenidos[ret.Impuesto] = helpers.SumStrings(retenidos[ret.Impuesto], ret.Importe)
+		}
+
+		relacionados := make([]string, 0, len(c.CFDIsRelacionados))
+		for _, rel := range c.CFDIsRelacionados {
+			relacionados = append(relacionados, rel.UUID)
+		}
+
+		f.addRow(table, map[string]string{
+			"uuid":                        tfd.UUID,
+			"version":                     c.Version,
+			"serie":                       c.Serie,
+			"folio":                       c.Folio,
+			"fecha":                       c.Fecha,
+			"no_certificado":              c.NoCertificado,
+			"subtotal":                    c.SubTotal,
+			"descuento":                   c.Descuento,
+			"total":                       c.Total,
+			"moneda":                      c.Moneda,
+			"tipo_cambio":                 c.TipoCambio,
+			"tipo_comprobante":            c.TipoComprobante,
+			"metodo_pago":                 c.MetodoPago,
+			"forma_pago":                  c.FormaPago,
+			"condiciones_pago":            c.CondicionesPago,
+			"lugar_expedicion":            c.LugarExpedicion,
+			"exportacion":                 c.Exportacion,
+			"confirmacion":                c.Confirmacion,
+			"emisor_rfc":                  c.Emisor.RFC,
+			"emisor_nombre":               c.Emisor.Nombre,
+			"emisor_regimen_fiscal":       c.Emisor.RegimenFiscal,
+			"emisor_fac_atr_adquirente":   c.Emisor.FacAtrAdquirente,
+			"receptor_rfc":                c.Receptor.RFC,
+			"receptor_nombre":             c.Receptor.Nombre,
+			"receptor_domicilio_fiscal":   c.Receptor.DomicilioFiscalReceptor,
+			"receptor_residencia_fiscal":  c.Receptor.ResidenciaFiscal,
+			"receptor_num_reg_id_trib":    c.Receptor.NumRegIdTrib,
+			"receptor_regimen_fiscal":     c.Receptor.RegimenFiscalReceptor,
+			"receptor_uso_cfdi":           c.Receptor.UsoCFDI,
+			"total_impuestos_trasladados": c.Impuestos.TotalImpuestosTrasladados,
+			"total_impuestos_retenidos":   c.Impuestos.TotalImpuestosRetenidos,
+			"iva_trasladado":              trasladados[impuestoIVA],
+			"ieps_trasladado":             trasladados[impuestoIEPS],
+			"isr_retenido":                retenidos[impuestoISR],
+			"iva_retenido":                retenidos[impuestoIVA],
+			"ieps_retenido":               retenidos[impuestoIEPS],
+			"cfdis_relacionados":          strings.Join(relacionados, " "),
+			"complementos":                c.Complementos,
+			"addendas":                    c.Addendas,
+			"fecha_timbrado":              tfd.FechaTimbrado,
+			"rfc_prov_cert":               tfd.RfcProvCert,
+			"no_certificado_sat":          tfd.NoCertificadoSAT,
+		})
+	}
+
+	return table
+}
+
+// Conceptos returns one row per concept of every document.
+func (f *CFDI40Formatter) Conceptos(docs []models.CFDI40Data) *Table {
+	table := newTable(TableConceptos, conceptosColumns, f.config)
+
+	for _, doc := range docs {
+		c := doc.CFDI40
+		uuid := firstTFD(doc).UUID
+
+		for _, concepto := range c.Conceptos {
+			var trasladados, retenidos = map[string]string{}, map[string]string{}
+			for _, tr := range concepto.Traslados {
+				trasladados[tr.Impuesto] = helpers.SumStrings(trasladados[tr.Impuesto], tr.Importe)
+			}
+			for _, ret := range concepto.Retenciones {
+				retenidos[ret.Impuesto] = helpers.SumStrings(retenidos[ret.Impuesto], ret.Importe)
+			}
+
+			f.addRow(table, map[string]string{
+				"uuid":              uuid,
+				"serie":             c.Serie,
+				"folio":             c.Folio,
+				"fecha":             c.Fecha,
+				"emisor_rfc":        c.Emisor.RFC,
+				"receptor_rfc":      c.Receptor.RFC,
+				"clave_prod_serv":   concepto.ClaveProdServ,
+				"no_identificacion": concepto.NoIdentificacion,
+				"cantidad":          concepto.Cantidad,
+				"clave_unidad":      concepto.ClaveUnidad,
+				"unidad":            concepto.Unidad,
+				"descripcion":       concepto.Descripcion,
+				"valor_unitario":    concepto.ValorUnitario,
+				"importe":           concepto.Importe,
+				"descuento":         concepto.Descuento,
+				"objeto_imp":        concepto.ObjetoImp,
+				"iva_trasladado":    trasladados[impuestoIVA],
+				"ieps_trasladado":   trasladados[impuestoIEPS],
+				"isr_retenido":      retenidos[impuestoISR],
+				"iva_retenido":      retenidos[impuestoIVA],
+				"ieps_retenido":     retenidos[impuestoIEPS],
+			})
+		}
+	}
+
+	return table
+}
+
+// Pagos returns one row per Pago of every Pagos 2.0 complement.
+func (f *CFDI40Formatter) Pagos(docs []models.CFDI40Data) *Table {
+	table := newTable(TablePagos, pagosColumns, f.config)
+
+	for _, doc := range docs {
+		c := doc.CFDI40
+		uuid := firstTFD(doc).UUID
+
+		for _, pagos := range doc.Pagos20 {
+			for _, pago := range pagos.Pagos {
+				var trasladados, retenidos = map[string]string{}, map[string]string{}
+				for _, impuestos := range pago.ImpuestosP {
+					for _, tr := range impuestos.TrasladosP {
+						trasladados[tr.ImpuestoP] = helpers.SumStrings(trasladados[tr.ImpuestoP], tr.ImporteP)
+					}
+					for _, ret := range impuestos.RetencionesP {
+						retenidos[ret.ImpuestoP] = helpers.SumStrings(retenidos[ret.ImpuestoP], ret.ImporteP)
+					}
+				}
+
+				f.addRow(table, map[string]string{
+					"uuid":               uuid,
+					"serie":              c.Serie,
+					"folio":              c.Folio,
+					"emisor_rfc":         c.Emisor.RFC,
+					"receptor_rfc":       c.Receptor.RFC,
+					"version_pagos":      pagos.Version,
+					"fecha_pago":         pago.FechaPago,
+					"forma_de_pago_p":    pago.FormaDePagoP,
+					"moneda_p":           pago.MonedaP,
+					"tipo_cambio_p":      pago.TipoCambioP,
+					"monto":              pago.Monto,
+					"num_operacion":      pago.NumOperacion,
+					"rfc_emisor_cta_ord": pago.RfcEmisorCtaOrd,
+					"nom_banco_ord_ext":  pago.NomBancoOrdExt,
+					"cta_ordenante":      pago.CtaOrdenante,
+					"rfc_emisor_cta_ben": pago.RfcEmisorCtaBen,
+					"cta_beneficiario":   pago.CtaBeneficiario,
+					"tipo_cad_pago":      pago.TipoCadPago,
+					"iva_trasladado_p":   trasladados[impuestoIVA],
+					"ieps_trasladado_p":  trasladados[impuestoIEPS],
+					"isr_retenido_p":     retenidos[impuestoISR],
+					"iva_retenido_p":     retenidos[impuestoIVA],
+					"ieps_retenido_p":    retenidos[impuestoIEPS],
+				})
+			}
+		}
+	}
+
+	return table
+}
+
+// DoctosRelacionados returns one row per DoctoRelacionado of every Pago.
+func (f *CFDI40Formatter) DoctosRelacionados(docs []models.CFDI40Data) *Table {
+	table := newTable(TableDoctosRelacionados, doctosRelacionadosColumns, f.config)
+
+	for _, doc := range docs {
+		c := doc.CFDI40
+		uuid := firstTFD(doc).UUID
+
+		for _, pagos := range doc.Pagos20 {
+			for _, pago := range pagos.Pagos {
+				for _, docto := range pago.DoctoRelacionado {
+					f.addRow(table, map[string]string{
+						"uuid":               uuid,
+						"emisor_rfc":         c.Emisor.RFC,
+						"receptor_rfc":       c.Receptor.RFC,
+						"fecha_pago":         pago.FechaPago,
+						"moneda_p":           pago.MonedaP,
+						"tipo_cambio_p":      pago.TipoCambioP,
+						"id_documento":       strings.ToUpper(docto.IdDocumento),
+						"serie_dr":           docto.Serie,
+						"folio_dr":           docto.Folio,
+						"moneda_dr":          docto.MonedaDR,
+						"equivalencia_dr":    docto.EquivalenciaDR,
+						"num_parcialidad":    docto.NumParcialidad,
+						"imp_saldo_ant":      docto.ImpSaldoAnt,
+						"imp_pagado":         docto.ImpPagado,
+						"imp_saldo_insoluto": docto.ImpSaldoInsoluto,
+						"objeto_imp_dr":      docto.ObjetoImpDR,
+					})
+				}
+			}
+		}
+	}
+
+	return table
+}
+
+// Nomina returns one row per percepción, deducción and otro pago of every Nómina 1.2 complement.
+func (f *CFDI40Formatter) Nomina(docs []models.CFDI40Data) *Table {
+	table := newTable(TableNomina, nominaColumns, f.config)
+
+	for _, doc := range docs {
+		c := doc.CFDI40
+		uuid := firstTFD(doc).UUID
+
+		for _, nomina := range doc.Nomina12 {
+			base := func(movimiento string) map[string]string {
+				return map[string]string{
+					"uuid":               uuid,
+					"emisor_rfc":         c.Emisor.RFC,
+					"receptor_rfc":       c.Receptor.RFC,
+					"curp":               nomina.Receptor.Curp,
+					"num_empleado":       nomina.Receptor.NumEmpleado,
+					"tipo_nomina":        nomina.TipoNomina,
+					"fecha_pago":         nomina.FechaPago,
+					"fecha_inicial_pago": nomina.FechaInicialPago,
+					"fecha_final_pago":   nomina.FechaFinalPago,
+					"num_dias_pagados":   nomina.NumDiasPagados,
+					"movimiento":         movimiento,
+				}
+			}
+
+			for _, percepcion := range nomina.Percepciones.Percepcion {
+				row := base("percepcion")
+				row["tipo"] = percepcion.TipoPercepcion
+				row["clave"] = percepcion.Clave
+				row["concepto"] = percepcion.Concepto
+				row["importe_gravado"] = percepcion.ImporteGravado
+				row["importe_exento"] = percepcion.ImporteExento
+				row["importe"] = helpers.SumStrings(percepcion.ImporteGravado, percepcion.ImporteExento)
+				f.addRow(table, row)
+			}
+			for _, deduccion := range nomina.Deducciones.Deduccion {
+				row := base("deduccion")
+				row["tipo"] = deduccion.TipoDeduccion
+				row["clave"] = deduccion.Clave
+				row["concepto"] = deduccion.Concepto
+				row["importe"] = deduccion.Importe
+				f.addRow(table, row)
+			}
+			for _, otroPago := range nomina.OtrosPagos.OtroPago {
+				row := base("otro_pago")
+				row["tipo"] = otroPago.TipoOtroPago
+				row["clave"] = otroPago.Clave
+				row["concepto"] = otroPago.Concepto
+				row["importe"] = otroPago.Importe
+				f.addRow(table, row)
+			}
+		}
+	}
+
+	return table
+}
+
+// addRow appends a row to the table following the column order, using EmptyChar for missing values.
+func (f *CFDI40Formatter) addRow(table *Table, values map[string]string) {
+	row := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		row[i] = values[col.Key]
+		if row[i] == "" {
+			row[i] = f.config.EmptyChar
+		}
+	}
+	table.Rows = append(table.Rows, row)
+}
+
+// firstTFD returns the first TFD11 of the document or an empty one.
+func firstTFD(doc models.CFDI40Data) models.TFD11 {
+	if len(doc.TFD11) > 0 {
+		return doc.TFD11[0]
+	}
+	return models.TFD11{}
+}
@@ -0,0 +1,23 @@
+package formatters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteCSV writes the table as CSV, including a header row, to the given writer.
+func WriteCSV(w io.Writer, table *Table, config Config) error {
+	writer := csv.NewWriter(w)
+	if config.Comma != 0 {
+		writer.Comma = config.Comma
+	}
+
+	if err := writer.Write(table.Headers()); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+	if err := writer.WriteAll(table.Rows); err != nil {
+		return fmt.Errorf("error writing CSV rows: %w", err)
+	}
+	return nil
+}
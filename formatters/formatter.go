@@ -0,0 +1,91 @@
+// Package formatters convierte los CFDI parseados en filas planas con un conjunto de columnas estable,
+// listas para exportarse a CSV o a una hoja de calculo.
+package formatters
+
+// Nombres de las tablas que genera el formatter.
+const (
+	TableComprobantes       = "comprobantes"
+	TableConceptos          = "conceptos"
+	TablePagos              = "pagos"
+	TableDoctosRelacionados = "doctos_relacionados"
+	TableNomina             = "nomina"
+)
+
+// ColumnType indica el tipo de dato de una columna.
+type ColumnType int
+
+const (
+	// ColumnString es una columna de texto.
+	ColumnString ColumnType = iota
+	// ColumnDecimal es una columna numerica representada como decimal.
+	ColumnDecimal
+)
+
+// Column describe una columna de una tabla plana.
+type Column struct {
+	Key    string
+	Header string
+	Type   ColumnType
+}
+
+// Table es un conjunto de filas planas con un conjunto de columnas estable.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]string
+}
+
+// Headers retorna los encabezados de las columnas de la tabla.
+func (t *Table) Headers() []string {
+	headers := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+	}
+	return headers
+}
+
+// Config contiene la configuración para los formatters.
+type Config struct {
+	// EmptyChar es el valor usado cuando un campo no tiene valor.
+	EmptyChar string
+	// ColumnNames permite renombrar los encabezados de las columnas por su Key.
+	ColumnNames map[string]string
+	// Comma es el separador usado al escribir CSV.
+	Comma rune
+}
+
+// NewDefaultConfig retorna una configuración por defecto para los formatters.
+func NewDefaultConfig() Config {
+	return Config{
+		EmptyChar:   "",
+		ColumnNames: map[string]string{},
+		Comma:       ',',
+	}
+}
+
+// newTable crea una tabla vacia aplicando los nombres de columna de la configuración.
+func newTable(name string, columns []Column, config Config) *Table {
+	cols := make([]Column, len(columns))
+	for i, col := range columns {
+		cols[i] = col
+		cols[i].Header = col.Key
+		if header, ok := config.ColumnNames[col.Key]; ok && header != "" {
+			cols[i].Header = header
+		}
+	}
+	return &Table{
+		Name:    name,
+		Columns: cols,
+		Rows:    [][]string{},
+	}
+}
+
+// str crea una columna de texto.
+func str(key string) Column {
+	return Column{Key: key, Type: ColumnString}
+}
+
+// dec crea una columna decimal.
+func dec(key string) Column {
+	return Column{Key: key, Type: ColumnDecimal}
+}
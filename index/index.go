@@ -0,0 +1,254 @@
+// Package index indexa CFDI parseados por UUID para detectar duplicados y consultarlos en memoria.
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ErrMissingUUID se retorna al agregar un documento sin TimbreFiscalDigital.
+var ErrMissingUUID = errors.New("document has no TimbreFiscalDigital UUID")
+
+// fechaLayout es el formato del atributo Fecha del Comprobante.
+const fechaLayout = "2006-01-02T15:04:05"
+
+// Status es el resultado de agregar un documento al indice.
+type Status int
+
+const (
+	// Added indica que el UUID no existia en el indice.
+	Added Status = iota
+	// Duplicate indica que el UUID ya existia con el mismo contenido.
+	Duplicate
+	// Conflict indica que el UUID ya existia con contenido distinto.
+	Conflict
+)
+
+// Variant es una version de un documento cuyo contenido difiere del indexado.
+type Variant struct {
+	Source string
+	Hash   string
+	Data   models.CFDI40Data
+}
+
+// Entry es un documento indexado por UUID.
+type Entry struct {
+	UUID string
+	// Hash es el hash del Comprobante sin Addenda.
+	Hash string
+	Data models.CFDI40Data
+	// Sources son todas las fuentes en las que se encontro el documento con este contenido.
+	Sources []string
+	// Variants son las versiones del mismo UUID con contenido distinto.
+	Variants []Variant
+}
+
+// Query filtra los documentos del indice. Los campos vacios no filtran.
+type Query struct {
+	RfcEmisor       string
+	RfcReceptor     string
+	TipoComprobante string
+	// From y To limitan la Fecha del Comprobante, ambos inclusivos.
+	From time.Time
+	To   time.Time
+}
+
+// Index es un indice en memoria de CFDI por UUID, seguro para uso concurrente.
+type Index struct {
+	mu         sync.RWMutex
+	entries    map[string]*Entry
+	byEmisor   map[string][]string
+	byReceptor map[string][]string
+	byTipo     map[string][]string
+}
+
+// New creates an empty Index.
+func New() *Index {
+	return &Index{
+		entries:    map[string]*Entry{},
+		byEmisor:   map[string][]string{},
+		byReceptor: map[string][]string{},
+		byTipo:     map[string][]string{},
+	}
+}
+
+// Add indexes a document found in source, keyed by its TFD UUID.
+// Returns Duplicate if the UUID was already indexed with the same content hash,
+// or Conflict if the content differs.
+func (ix *Index) Add(source string, doc models.CFDI40Data) (Status, error) {
+	if len(doc.TFD11) == 0 || doc.TFD11[0].UUID == "" {
+		return Added, ErrMissingUUID
+	}
+	uuid := strings.ToUpper(doc.TFD11[0].UUID)
+
+	hash, err := ContentHash(doc)
+	if err != nil {
+		return Added, err
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if entry, ok := ix.entries[uuid]; ok {
+		if entry.Hash == hash {
+			entry.Sources = append(entry.Sources, source)
+			return Duplicate, nil
+		}
+		entry.Variants = append(entry.Variants, Variant{Source: source, Hash: hash, Data: doc})
+		return Conflict, nil
+	}
+
+	ix.entries[uuid] = &Entry{
+		UUID:    uuid,
+		Hash:    hash,
+		Data:    doc,
+		Sources: []string{source},
+	}
+	c := doc.CFDI40
+	ix.byEmisor[strings.ToUpper(c.Emisor.RFC)] = append(ix.byEmisor[strings.ToUpper(c.Emisor.RFC)], uuid)
+	ix.byReceptor[strings.ToUpper(c.Receptor.RFC)] = append(ix.byReceptor[strings.ToUpper(c.Receptor.RFC)], uuid)
+	ix.byTipo[c.TipoComprobante] = append(ix.byTipo[c.TipoComprobante], uuid)
+	return Added, nil
+}
+
+// Get returns the entry of the given UUID.
+func (ix *Index) Get(uuid string) (Entry, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	entry, ok := ix.entries[strings.ToUpper(uuid)]
+	if !ok {
+		return Entry{}, false
+	}
+	return entry.clone(), true
+}
+
+// Len returns the number of distinct UUIDs indexed.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.entries)
+}
+
+// Duplicates returns the entries found in more than one source with the same content.
+func (ix *Index) Duplicates() []Entry {
+	return ix.filter(func(e *Entry) bool { return len(e.Sources) > 1 })
+}
+
+// Conflicts returns the entries found with different content for the same UUID.
+func (ix *Index) Conflicts() []Entry {
+	return ix.filter(func(e *Entry) bool { return len(e.Variants) > 0 })
+}
+
+// Query returns the entries matching every non-empty field of q, sorted by Fecha and UUID.
+func (ix *Index) Query(q Query) []Entry {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var candidates []string
+	switch {
+	case q.RfcEmisor != "":
+		candidates = ix.byEmisor[strings.ToUpper(q.RfcEmisor)]
+	case q.RfcReceptor != "":
+		candidates = ix.byReceptor[strings.ToUpper(q.RfcReceptor)]
+	case q.TipoComprobante != "":
+		candidates = ix.byTipo[q.TipoComprobante]
+	default:
+		candidates = make([]string, 0, len(ix.entries))
+		for uuid := range ix.entries {
+			candidates = append(candidates, uuid)
+		}
+	}
+
+	var result []Entry
+	for _, uuid := range candidates {
+		entry := ix.entries[uuid]
+		if matches(entry, q) {
+			result = append(result, entry.clone())
+		}
+	}
+	sortEntries(result)
+	return result
+}
+
+func (ix *Index) filter(keep func(*Entry) bool) []Entry {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var result []Entry
+	for _, entry := range ix.entries {
+		if keep(entry) {
+			result = append(result, entry.clone())
+		}
+	}
+	sortEntries(result)
+	return result
+}
+
+func (e *Entry) clone() Entry {
+	out := *e
+	out.Sources = append([]string(nil), e.Sources...)
+	out.Variants = append([]Variant(nil), e.Variants...)
+	return out
+}
+
+func matches(entry *Entry, q Query) bool {
+	c := entry.Data.CFDI40
+	if q.RfcEmisor != "" && !strings.EqualFold(c.Emisor.RFC, q.RfcEmisor) {
+		return false
+	}
+	if q.RfcReceptor != "" && !strings.EqualFold(c.Receptor.RFC, q.RfcReceptor) {
+		return false
+	}
+	if q.TipoComprobante != "" && c.TipoComprobante != q.TipoComprobante {
+		return false
+	}
+	if q.From.IsZero() && q.To.IsZero() {
+		return true
+	}
+
+	fecha, err := time.Parse(fechaLayout, c.Fecha)
+	if err != nil {
+		return false
+	}
+	if !q.From.IsZero() && fecha.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && fecha.After(q.To) {
+		return false
+	}
+	return true
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Data.CFDI40.Fecha != entries[j].Data.CFDI40.Fecha {
+			return entries[i].Data.CFDI40.Fecha < entries[j].Data.CFDI40.Fecha
+		}
+		return entries[i].UUID < entries[j].UUID
+	})
+}
+
+// ContentHash returns the SHA-256 of the parsed Comprobante and its complements, ignoring the Addenda.
+// Two documents parsed with the same HandlerConfig have the same hash when only their addendas differ.
+func ContentHash(doc models.CFDI40Data) (string, error) {
+	doc.CFDI40.Addendas = ""
+	doc.CFDI40.AddendasRefs = nil
+	doc.Addenda = nil
+
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("error hashing document: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,333 @@
+// Package ledger arma la cartera de cuentas por cobrar a partir de facturas PPD y recibos de Pagos 2.0.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ErrMissingUUID se retorna al agregar un documento sin TimbreFiscalDigital.
+var ErrMissingUUID = errors.New("document has no TimbreFiscalDigital UUID")
+
+// ErrNotPPD se retorna al agregar una factura que no es de ingreso con MetodoPago PPD.
+var ErrNotPPD = errors.New("document is not an ingreso invoice with MetodoPago PPD")
+
+// IssueKind es el tipo de inconsistencia encontrada en la cartera.
+type IssueKind int
+
+const (
+	// Overpayment indica que la suma de ImpPagado excede el Total de la factura.
+	Overpayment IssueKind = iota
+	// ParcialidadGap indica que falta un NumParcialidad en la secuencia.
+	ParcialidadGap
+	// DuplicateParcialidad indica que un NumParcialidad se pago en mas de un recibo.
+	DuplicateParcialidad
+	// SaldoMismatch indica que ImpSaldoAnt o ImpSaldoInsoluto no siguen la progresion esperada.
+	SaldoMismatch
+	// UnknownInvoice indica un pago a un IdDocumento que no esta en la cartera.
+	UnknownInvoice
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case Overpayment:
+		return "overpayment"
+	case ParcialidadGap:
+		return "parcialidad_gap"
+	case DuplicateParcialidad:
+		return "duplicate_parcialidad"
+	case SaldoMismatch:
+		return "saldo_mismatch"
+	case UnknownInvoice:
+		return "unknown_invoice"
+	}
+	return "unknown"
+}
+
+// Issue es una inconsistencia de la cartera.
+type Issue struct {
+	Kind IssueKind
+	// InvoiceUUID es el UUID de la factura, o el IdDocumento del pago para UnknownInvoice.
+	InvoiceUUID string
+	// PaymentUUID es el UUID del recibo de pago, vacio si la inconsistencia es de la factura.
+	PaymentUUID    string
+	NumParcialidad int
+	Message        string
+}
+
+// Payment es el pago de una parcialidad de una factura, tomado de un DoctoRelacionado20.
+// Los importes estan en la moneda de la factura (MonedaDR).
+type Payment struct {
+	// UUID es el UUID del recibo de pago.
+	UUID             string
+	IdDocumento      string
+	FechaPago        string
+	NumParcialidad   int
+	MonedaDR         string
+	ImpSaldoAnt      decimal.Decimal
+	ImpPagado        decimal.Decimal
+	ImpSaldoInsoluto decimal.Decimal
+}
+
+// Invoice es una factura PPD con sus pagos.
+type Invoice struct {
+	UUID        string
+	Serie       string
+	Folio       string
+	Fecha       string
+	RfcEmisor   string
+	RfcReceptor string
+	Moneda      string
+	Total       decimal.Decimal
+	// Payments estan ordenados por NumParcialidad y FechaPago.
+	Payments []Payment
+	Paid     decimal.Decimal
+	// Balance es Total menos Paid; negativo si hay sobrepago.
+	Balance decimal.Decimal
+}
+
+// Report es el estado de la cartera.
+type Report struct {
+	// Invoices son todas las facturas en el orden en que se agregaron.
+	Invoices []Invoice
+	// Open son las facturas con saldo pendiente.
+	Open []Invoice
+	// Issues son las inconsistencias encontradas.
+	Issues []Issue
+}
+
+// Ledger relaciona facturas PPD con los pagos de sus recibos, seguro para uso concurrente.
+type Ledger struct {
+	mu       sync.RWMutex
+	invoices map[string]*Invoice
+	order    []string
+	payments map[string][]Payment
+	receipts map[string]bool
+}
+
+// New creates an empty Ledger.
+func New() *Ledger {
+	return &Ledger{
+		invoices: map[string]*Invoice{},
+		payments: map[string][]Payment{},
+		receipts: map[string]bool{},
+	}
+}
+
+// Add adds a parsed document: payment receipts (TipoComprobante P) and ingreso invoices with MetodoPago PPD.
+// Any other document, such as a PUE invoice, an egreso or a traslado, is ignored.
+func (l *Ledger) Add(doc models.CFDI40Data) error {
+	c := doc.CFDI40
+	switch {
+	case c.TipoComprobante == "P":
+		return l.AddPayment(doc)
+	case c.TipoComprobante == "I" && c.MetodoPago == "PPD":
+		return l.AddInvoice(doc)
+	}
+	return nil
+}
+
+// AddInvoice adds an ingreso invoice with MetodoPago PPD. Any other document returns ErrNotPPD.
+func (l *Ledger) AddInvoice(doc models.CFDI40Data) error {
+	uuid, err := documentUUID(doc)
+	if err != nil {
+		return err
+	}
+	c := doc.CFDI40
+	if c.TipoComprobante != "I" || c.MetodoPago != "PPD" {
+		return fmt.Errorf("%s: %w", uuid, ErrNotPPD)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.invoices[uuid]; ok {
+		return nil
+	}
+	l.invoices[uuid] = &Invoice{
+		UUID:        uuid,
+		Serie:       c.Serie,
+		Folio:       c.Folio,
+		Fecha:       c.Fecha,
+		RfcEmisor:   c.Emisor.RFC,
+		RfcReceptor: c.Receptor.RFC,
+		Moneda:      c.Moneda,
+		Total:       helpers.TryParseDecimal(c.Total),
+	}
+	l.order = append(l.order, uuid)
+	return nil
+}
+
+// AddPayment adds every DoctoRelacionado of the Pagos 2.0 complements of a payment receipt.
+// A receipt whose UUID was already added is ignored.
+func (l *Ledger) AddPayment(doc models.CFDI40Data) error {
+	uuid, err := documentUUID(doc)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.receipts[uuid] {
+		return nil
+	}
+	l.receipts[uuid] = true
+	for _, pagos := range doc.Pagos20 {
+		l.addPagos(uuid, pagos)
+	}
+	return nil
+}
+
+// AddPagos adds the payments of a Pagos complement issued in the receipt with the given UUID.
+// Pagos 1.0 receipts can be added after normalizing them with Pagos10Handler.ToPagos20.
+// A receipt whose UUID was already added is ignored.
+func (l *Ledger) AddPagos(receiptUUID string, pagos models.Pagos20Data) {
+	receiptUUID = strings.ToUpper(receiptUUID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.receipts[receiptUUID] {
+		return
+	}
+	l.receipts[receiptUUID] = true
+	l.addPagos(receiptUUID, pagos)
+}
+
+func (l *Ledger) addPagos(receiptUUID string, pagos models.Pagos20Data) {
+	for _, pago := range pagos.Pagos {
+		for _, docto := range pago.DoctoRelacionado {
+			idDocumento := strings.ToUpper(docto.IdDocumento)
+			numParcialidad, _ := strconv.Atoi(docto.NumParcialidad)
+			l.payments[idDocumento] = append(l.payments[idDocumento], Payment{
+				UUID:             receiptUUID,
+				IdDocumento:      idDocumento,
+				FechaPago:        pago.FechaPago,
+				NumParcialidad:   numParcialidad,
+				MonedaDR:         docto.MonedaDR,
+				ImpSaldoAnt:      helpers.TryParseDecimal(docto.ImpSaldoAnt),
+				ImpPagado:        helpers.TryParseDecimal(docto.ImpPagado),
+				ImpSaldoInsoluto: helpers.TryParseDecimal(docto.ImpSaldoInsoluto),
+			})
+		}
+	}
+}
+
+// Invoice returns the invoice with the given UUID and its payments.
+func (l *Ledger) Invoice(uuid string) (Invoice, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	invoice, ok := l.invoices[strings.ToUpper(uuid)]
+	if !ok {
+		return Invoice{}, false
+	}
+	result, _ := l.reconcile(invoice)
+	return result, true
+}
+
+// Report links every payment to its invoice and reports open balances and inconsistencies.
+func (l *Ledger) Report() Report {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var report Report
+	for _, uuid := range l.order {
+		invoice, issues := l.reconcile(l.invoices[uuid])
+		report.Invoices = append(report.Invoices, invoice)
+		if invoice.Balance.IsPositive() {
+			report.Open = append(report.Open, invoice)
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	unknown := make([]string, 0)
+	for idDocumento := range l.payments {
+		if _, ok := l.invoices[idDocumento]; !ok {
+			unknown = append(unknown, idDocumento)
+		}
+	}
+	sort.Strings(unknown)
+	for _, idDocumento := range unknown {
+		for _, payment := range l.payments[idDocumento] {
+			report.Issues = append(report.Issues, Issue{
+				Kind:           UnknownInvoice,
+				InvoiceUUID:    idDocumento,
+				PaymentUUID:    payment.UUID,
+				NumParcialidad: payment.NumParcialidad,
+				Message:        "payment to an invoice not found in the ledger",
+			})
+		}
+	}
+	return report
+}
+
+// reconcile returns a copy of the invoice with its payments and the inconsistencies found.
+func (l *Ledger) reconcile(invoice *Invoice) (Invoice, []Issue) {
+	result := *invoice
+	result.Payments = append([]Payment(nil), l.payments[invoice.UUID]...)
+	sort.SliceStable(result.Payments, func(i, j int) bool {
+		if result.Payments[i].NumParcialidad != result.Payments[j].NumParcialidad {
+			return result.Payments[i].NumParcialidad < result.Payments[j].NumParcialidad
+		}
+		return result.Payments[i].FechaPago < result.Payments[j].FechaPago
+	})
+
+	var issues []Issue
+	issue := func(kind IssueKind, payment *Payment, format string, args ...interface{}) {
+		item := Issue{Kind: kind, InvoiceUUID: invoice.UUID, Message: fmt.Sprintf(format, args...)}
+		if payment != nil {
+			item.PaymentUUID = payment.UUID
+			item.NumParcialidad = payment.NumParcialidad
+		}
+		issues = append(issues, item)
+	}
+
+	result.Paid = decimal.Zero
+	saldo := invoice.Total
+	expected := 1
+	for i := range result.Payments {
+		payment := &result.Payments[i]
+		result.Paid = result.Paid.Add(payment.ImpPagado)
+
+		switch {
+		case i > 0 && payment.NumParcialidad == result.Payments[i-1].NumParcialidad:
+			issue(DuplicateParcialidad, payment, "parcialidad %d paid more than once", payment.NumParcialidad)
+		case payment.NumParcialidad > expected:
+			issue(ParcialidadGap, payment, "parcialidades %d to %d are missing", expected, payment.NumParcialidad-1)
+		}
+		if payment.NumParcialidad >= expected {
+			expected = payment.NumParcialidad + 1
+		}
+
+		if !payment.ImpSaldoAnt.Equal(saldo) {
+			issue(SaldoMismatch, payment, "ImpSaldoAnt %s, expected %s", payment.ImpSaldoAnt.StringFixed(2), saldo.StringFixed(2))
+		}
+		if !payment.ImpSaldoAnt.Sub(payment.ImpPagado).Equal(payment.ImpSaldoInsoluto) {
+			issue(SaldoMismatch, payment, "ImpSaldoInsoluto %s, expected %s", payment.ImpSaldoInsoluto.StringFixed(2), payment.ImpSaldoAnt.Sub(payment.ImpPagado).StringFixed(2))
+		}
+		saldo = payment.ImpSaldoInsoluto
+	}
+
+	result.Balance = invoice.Total.Sub(result.Paid)
+	if result.Balance.IsNegative() {
+		issue(Overpayment, nil, "paid %s of a total of %s", result.Paid.StringFixed(2), invoice.Total.StringFixed(2))
+	}
+	return result, issues
+}
+
+func documentUUID(doc models.CFDI40Data) (string, error) {
+	if len(doc.TFD11) == 0 || doc.TFD11[0].UUID == "" {
+		return "", ErrMissingUUID
+	}
+	return strings.ToUpper(doc.TFD11[0].UUID), nil
+}
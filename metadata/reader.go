@@ -0,0 +1,208 @@
+// Package metadata lee los archivos de metadata de la descarga masiva del SAT y los concilia contra los CFDI parseados.
+package metadata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Estatus es el estatus de un comprobante en la metadata del SAT.
+type Estatus int
+
+const (
+	// EstatusCancelado corresponde al valor 0 de la metadata.
+	EstatusCancelado Estatus = 0
+	// EstatusVigente corresponde al valor 1 de la metadata.
+	EstatusVigente Estatus = 1
+)
+
+// Record es un registro tipado del archivo de metadata.
+type Record struct {
+	UUID                  string          `json:"uuid"`
+	RfcEmisor             string          `json:"rfc_emisor"`
+	NombreEmisor          string          `json:"nombre_emisor"`
+	RfcReceptor           string          `json:"rfc_receptor"`
+	NombreReceptor        string          `json:"nombre_receptor"`
+	RfcPac                string          `json:"rfc_pac"`
+	FechaEmision          time.Time       `json:"fecha_emision"`
+	FechaCertificacionSat time.Time       `json:"fecha_certificacion_sat"`
+	Monto                 decimal.Decimal `json:"monto"`
+	EfectoComprobante     string          `json:"efecto_comprobante"`
+	Estatus               Estatus         `json:"estatus"`
+	FechaCancelacion      time.Time       `json:"fecha_cancelacion,omitempty"`
+	RfcACuentaTerceros    string          `json:"rfc_a_cuenta_terceros,omitempty"`
+	NombreACuentaTerceros string          `json:"nombre_a_cuenta_terceros,omitempty"`
+}
+
+// Cancelado indica si el comprobante esta cancelado.
+func (r Record) Cancelado() bool {
+	return r.Estatus == EstatusCancelado
+}
+
+// defaultColumns es el orden de las columnas cuando el archivo no trae encabezado.
+var defaultColumns = []string{
+	"uuid", "rfcemisor", "nombreemisor", "rfcreceptor", "nombrereceptor", "rfcpac",
+	"fechaemision", "fechacertificacionsat", "monto", "efectocomprobante", "estatus", "fechacancelacion",
+	"rfcacuentaterceros", "nombreacuentaterceros",
+}
+
+// dateLayouts son los formatos de fecha aceptados en la metadata.
+var dateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// ParseError es un error en una linea del archivo de metadata.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("error parsing metadata line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader lee registros de un archivo de metadata delimitado por tildes.
+type Reader struct {
+	scanner *bufio.Scanner
+	columns []string
+	line    int
+}
+
+// NewReader creates a new Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &Reader{scanner: scanner, columns: defaultColumns}
+}
+
+// Read returns the next record. It returns io.EOF when there are no more records.
+// The header line, if present, is used to map the columns by name.
+func (r *Reader) Read() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimPrefix(strings.TrimRight(r.scanner.Text(), "\r"), "\ufeff")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, "~")
+		if r.line == 1 && strings.EqualFold(strings.TrimSpace(fields[0]), "uuid") {
+			r.columns = make([]string, len(fields))
+			for i, f := range fields {
+				r.columns[i] = strings.ToLower(strings.TrimSpace(f))
+			}
+			continue
+		}
+
+		record, err := r.parseRecord(fields)
+		if err != nil {
+			return Record{}, &ParseError{Line: r.line, Err: err}
+		}
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, fmt.Errorf("error reading metadata: %w", err)
+	}
+	return Record{}, io.EOF
+}
+
+// ReadAll reads every remaining record.
+func (r *Reader) ReadAll() ([]Record, error) {
+	var records []Record
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// ReadFile reads every record of the metadata file at path.
+func ReadFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening metadata file: %w", err)
+	}
+	defer f.Close()
+	return NewReader(f).ReadAll()
+}
+
+func (r *Reader) parseRecord(fields []string) (Record, error) {
+	values := make(map[string]string, len(r.columns))
+	for i, col := range r.columns {
+		if i < len(fields) {
+			values[col] = strings.TrimSpace(fields[i])
+		}
+	}
+
+	record := Record{
+		UUID:                  strings.ToUpper(values["uuid"]),
+		RfcEmisor:             values["rfcemisor"],
+		NombreEmisor:          values["nombreemisor"],
+		RfcReceptor:           values["rfcreceptor"],
+		NombreReceptor:        values["nombrereceptor"],
+		RfcPac:                values["rfcpac"],
+		EfectoComprobante:     values["efectocomprobante"],
+		RfcACuentaTerceros:    values["rfcacuentaterceros"],
+		NombreACuentaTerceros: values["nombreacuentaterceros"],
+	}
+	if record.UUID == "" {
+		return Record{}, fmt.Errorf("missing Uuid")
+	}
+
+	var err error
+	if record.FechaEmision, err = parseDate(values["fechaemision"]); err != nil {
+		return Record{}, fmt.Errorf("invalid FechaEmision: %w", err)
+	}
+	if record.FechaCertificacionSat, err = parseDate(values["fechacertificacionsat"]); err != nil {
+		return Record{}, fmt.Errorf("invalid FechaCertificacionSat: %w", err)
+	}
+	if record.FechaCancelacion, err = parseDate(values["fechacancelacion"]); err != nil {
+		return Record{}, fmt.Errorf("invalid FechaCancelacion: %w", err)
+	}
+
+	if monto := values["monto"]; monto != "" {
+		if record.Monto, err = decimal.NewFromString(monto); err != nil {
+			return Record{}, fmt.Errorf("invalid Monto: %w", err)
+		}
+	}
+
+	switch values["estatus"] {
+	case "1":
+		record.Estatus = EstatusVigente
+	case "0":
+		record.Estatus = EstatusCancelado
+	default:
+		return Record{}, fmt.Errorf("invalid Estatus %q", values["estatus"])
+	}
+
+	return record, nil
+}
+
+// parseDate parses a metadata date; an empty value returns the zero time.
+func parseDate(val string) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+	var err error
+	for _, layout := range dateLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, val); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// AmountMismatch es un comprobante cuyo Total no coincide con el Monto de la metadata.
+type AmountMismatch struct {
+	UUID     string          `json:"uuid"`
+	Metadata decimal.Decimal `json:"metadata"`
+	CFDI     decimal.Decimal `json:"cfdi"`
+}
+
+// Report es el resultado de conciliar la metadata contra los CFDI parseados.
+type Report struct {
+	// MissingXML son los registros de metadata sin XML.
+	MissingXML []Record `json:"missing_xml"`
+	// MissingMetadata son los UUID de los XML sin registro en la metadata.
+	MissingMetadata []string `json:"missing_metadata"`
+	// Cancelled son los registros cancelados que tienen XML.
+	Cancelled []Record `json:"cancelled"`
+	// AmountMismatches son los comprobantes cuyo Total difiere del Monto de la metadata.
+	AmountMismatches []AmountMismatch `json:"amount_mismatches"`
+}
+
+// Reconcile compares the metadata records against the parsed documents, joined by TFD UUID.
+// Documents without TimbreFiscalDigital cannot be joined and are ignored.
+func Reconcile(records []Record, docs []models.CFDI40Data) Report {
+	report := Report{
+		MissingXML:       []Record{},
+		MissingMetadata:  []string{},
+		Cancelled:        []Record{},
+		AmountMismatches: []AmountMismatch{},
+	}
+
+	byUUID := make(map[string]Record, len(records))
+	for _, record := range records {
+		byUUID[record.UUID] = record
+	}
+
+	found := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if len(doc.TFD11) == 0 {
+			continue
+		}
+		uuid := doc.TFD11[0].UUID
+		found[uuid] = true
+
+		record, ok := byUUID[uuid]
+		if !ok {
+			report.MissingMetadata = append(report.MissingMetadata, uuid)
+			continue
+		}
+		if record.Cancelado() {
+			report.Cancelled = append(report.Cancelled, record)
+		}
+
+		total, err := decimal.NewFromString(doc.CFDI40.Total)
+		if err != nil || !total.Equal(record.Monto) {
+			report.AmountMismatches = append(report.AmountMismatches, AmountMismatch{
+				UUID:     uuid,
+				Metadata: record.Monto,
+				CFDI:     total,
+			})
+		}
+	}
+
+	for _, record := range records {
+		if !found[record.UUID] {
+			report.MissingXML = append(report.MissingXML, record)
+		}
+	}
+
+	return report
+}
@@ -3,15 +3,29 @@ package models
 
 // CFDI40Data es la estructura de datos para el CFDI 4.0
 // Incluye el CFDI40 y los TFD11 si los hay.
+// Warnings lista los elementos de otro namespace leidos como del CFDI en el modo LenientNamespaces.
 type CFDI40Data struct {
-	CFDI40           CFDI40                 `json:"cfdi40"`
-	TFD11            []TFD11                `json:"tfd11,omitempty"`
-	Pagos20          []Pagos20Data          `json:"pagos20,omitempty"`
-	VentaVehiculos11 []VentaVehiculos11Data `json:"venta_vehiculos_11,omitempty"`
-	Nomina12         []Nomina12Data         `json:"nomina_12,omitempty"`
+	CFDI40                      CFDI40                            `json:"cfdi40"`
+	TFD11                       []TFD11                           `json:"tfd11,omitempty"`
+	Pagos20                     []Pagos20Data                     `json:"pagos20,omitempty"`
+	VentaVehiculos11            []VentaVehiculos11Data            `json:"venta_vehiculos_11,omitempty"`
+	Nomina12                    []Nomina12Data                    `json:"nomina_12,omitempty"`
+	LeyendasFiscales10          []LeyendasFiscales10Data          `json:"leyendas_fiscales_10,omitempty"`
+	Donatarias11                []Donatarias11Data                `json:"donatarias_11,omitempty"`
+	Detallista                  []DetallistaData                  `json:"detallista,omitempty"`
+	ServicioParcial10           []ServicioParcial10Data           `json:"servicio_parcial_10,omitempty"`
+	TuristaPasajeroExtranjero10 []TuristaPasajeroExtranjero10Data `json:"turista_pasajero_extranjero_10,omitempty"`
+	ValesDeDespensa10           []ValesDeDespensa10Data           `json:"vales_de_despensa_10,omitempty"`
+	IngresosHidrocarburos10     []IngresosHidrocarburos10Data     `json:"ingresos_hidrocarburos_10,omitempty"`
+	GastosHidrocarburos10       []GastosHidrocarburos10Data       `json:"gastos_hidrocarburos_10,omitempty"`
+	RawComplementos             []RawComplement                   `json:"raw_complementos,omitempty"`
+	Addenda                     []Addenda                         `json:"addenda,omitempty"`
+	Warnings                    []string                          `json:"warnings,omitempty"`
 }
 
-// CFDI40 es la estructura de datos para el CFDI 4.0
+// CFDI40 es la estructura de datos para el CFDI 4.0.
+// Complementos y Addendas son los nombres locales de los elementos de primer nivel separados por
+// espacios; ComplementosRefs y AddendasRefs los identifican tambien por namespace y version.
 type CFDI40 struct {
 	Version           string            `json:"version"`
 	Serie             string            `json:"serie"`
@@ -38,6 +52,8 @@ type CFDI40 struct {
 	Impuestos         Impuestos         `json:"impuestos"`
 	Complementos      string            `json:"complementos"`
 	Addendas          string            `json:"addendas"`
+	ComplementosRefs  []ComplementRef   `json:"complementos_refs,omitempty"`
+	AddendasRefs      []ComplementRef   `json:"addendas_refs,omitempty"`
 	CFDIsRelacionados []CFDIRelacionado `json:"cfdis_relacionados,omitempty"`
 }
 
@@ -75,6 +91,7 @@ type Concepto40 struct {
 	Terceros         Terceros            `json:"terceros,omitempty"`
 	Traslados        []TrasladoConcepto  `json:"traslados,omitempty"`
 	Retenciones      []RetencionConcepto `json:"retenciones,omitempty"`
+	InstEducativas   *IEDU10Data         `json:"inst_educativas,omitempty"`
 }
 
 // Terceros es la estructura de datos para los terceros del CFDI 4.0
@@ -123,6 +140,30 @@ type RetencionConcepto struct {
 	Importe  string `json:"importe"`
 }
 
+// ComplementRef identifica un complemento o addenda de primer nivel.
+type ComplementRef struct {
+	Namespace string `json:"namespace"`
+	Local     string `json:"local"`
+	Version   string `json:"version"`
+}
+
+// RawComplement es un complemento no soportado por la libreria, con su XML original.
+type RawComplement struct {
+	ComplementRef
+	Raw []byte `json:"raw"`
+}
+
+// Addenda es el contenido de un elemento hijo de cfdi:Addenda.
+// Raw contiene el XML original del elemento y Data el resultado de su parser;
+// si el parser falla, Error contiene su mensaje y Data queda vacio.
+type Addenda struct {
+	Namespace string      `json:"namespace"`
+	Local     string      `json:"local"`
+	Raw       []byte      `json:"raw"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
 // CFDIRelacionado es la estructura de datos para un CFDI relacionado del CFDI 4.0
 type CFDIRelacionado struct {
 	UUID         string `json:"uuid"`
@@ -0,0 +1,79 @@
+package models
+
+// DetallistaData representa los datos de un complemento Detallista (AMECE).
+type DetallistaData struct {
+	Type                     string                           `json:"type"`
+	ContentVersion           string                           `json:"content_version"`
+	DocumentStructureVersion string                           `json:"document_structure_version"`
+	DocumentStatus           string                           `json:"document_status"`
+	EntityType               string                           `json:"entity_type"`
+	SpecialInstructions      []DetallistaSpecialInstruction   `json:"special_instructions"`
+	OrderIdentification      DetallistaReference              `json:"order_identification"`
+	AdditionalInformation    []DetallistaReferenceIdentifier  `json:"additional_information"`
+	DeliveryNote             DetallistaReference              `json:"delivery_note"`
+	BuyerGLN                 string                           `json:"buyer_gln"`
+	BuyerContact             string                           `json:"buyer_contact"`
+	SellerGLN                string                           `json:"seller_gln"`
+	SellerAlternateID        DetallistaReferenceIdentifier    `json:"seller_alternate_id"`
+	Currencies               []DetallistaCurrency             `json:"currencies"`
+	PaymentTerms             DetallistaPaymentTerms           `json:"payment_terms"`
+	LineItems                []DetallistaLineItem             `json:"line_items"`
+	TotalAmount              string                           `json:"total_amount"`
+	TotalAllowanceCharges    []DetallistaTotalAllowanceCharge `json:"total_allowance_charges"`
+}
+
+// DetallistaSpecialInstruction representa una instrucción especial de un complemento Detallista.
+type DetallistaSpecialInstruction struct {
+	Code string `json:"code"`
+	Text string `json:"text"`
+}
+
+// DetallistaReference representa una referencia con fecha de un complemento Detallista.
+type DetallistaReference struct {
+	ReferenceIdentification string `json:"reference_identification"`
+	ReferenceDate           string `json:"reference_date"`
+}
+
+// DetallistaReferenceIdentifier representa un identificador con su tipo de un complemento Detallista.
+type DetallistaReferenceIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// DetallistaCurrency representa la moneda de un complemento Detallista.
+type DetallistaCurrency struct {
+	CurrencyISOCode  string `json:"currency_iso_code"`
+	CurrencyFunction string `json:"currency_function"`
+	RateOfChange     string `json:"rate_of_change"`
+}
+
+// DetallistaPaymentTerms representa las condiciones de pago de un complemento Detallista.
+type DetallistaPaymentTerms struct {
+	PaymentTermsEvent        string `json:"payment_terms_event"`
+	PaymentTermsRelationTime string `json:"payment_terms_relation_time"`
+	NetPaymentTermsType      string `json:"net_payment_terms_type"`
+	TimePeriod               string `json:"time_period"`
+	Value                    string `json:"value"`
+}
+
+// DetallistaLineItem representa una partida de un complemento Detallista.
+type DetallistaLineItem struct {
+	Type             string `json:"type"`
+	Number           string `json:"number"`
+	GTIN             string `json:"gtin"`
+	Language         string `json:"language"`
+	LongText         string `json:"long_text"`
+	InvoicedQuantity string `json:"invoiced_quantity"`
+	UnitOfMeasure    string `json:"unit_of_measure"`
+	GrossPrice       string `json:"gross_price"`
+	NetPrice         string `json:"net_price"`
+	TotalGrossAmount string `json:"total_gross_amount"`
+	TotalNetAmount   string `json:"total_net_amount"`
+}
+
+// DetallistaTotalAllowanceCharge representa un cargo o descuento global de un complemento Detallista.
+type DetallistaTotalAllowanceCharge struct {
+	AllowanceOrChargeType string `json:"allowance_or_charge_type"`
+	SpecialServicesType   string `json:"special_services_type"`
+	Amount                string `json:"amount"`
+}
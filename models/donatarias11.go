@@ -0,0 +1,9 @@
+package models
+
+// Donatarias11Data representa los datos de un complemento Donatarias 1.1.
+type Donatarias11Data struct {
+	Version           string `json:"version"`
+	NoAutorizacion    string `json:"no_autorizacion"`
+	FechaAutorizacion string `json:"fecha_autorizacion"`
+	Leyenda           string `json:"leyenda"`
+}
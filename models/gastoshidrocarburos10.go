@@ -0,0 +1,62 @@
+package models
+
+// GastosHidrocarburos10Data representa los datos de un complemento Gastos de Hidrocarburos 1.0.
+type GastosHidrocarburos10Data struct {
+	Version         string      `json:"version"`
+	NumeroContrato  string      `json:"numero_contrato"`
+	AreaContractual string      `json:"area_contractual"`
+	Erogaciones     []Erogacion `json:"erogaciones"`
+}
+
+// Erogacion representa una erogación de un complemento Gastos de Hidrocarburos 1.0.
+type Erogacion struct {
+	TipoErogacion          string                     `json:"tipo_erogacion"`
+	MontocuErogacion       string                     `json:"montocu_erogacion"`
+	Porcentaje             string                     `json:"porcentaje"`
+	DocumentosRelacionados []DocumentoRelacionadoGCEH `json:"documentos_relacionados"`
+	Actividades            []ActividadGCEH            `json:"actividades"`
+	CentroCostos           []CentroCostosGCEH         `json:"centro_costos"`
+}
+
+// DocumentoRelacionadoGCEH representa un documento relacionado de una erogación de Gastos de Hidrocarburos 1.0.
+type DocumentoRelacionadoGCEH struct {
+	OrigenErogacion                string `json:"origen_erogacion"`
+	FolioFiscalVinculado           string `json:"folio_fiscal_vinculado"`
+	RFCProveedor                   string `json:"rfc_proveedor"`
+	MontoTotalIVA                  string `json:"monto_total_iva"`
+	MontoRetencionISR              string `json:"monto_retencion_isr"`
+	MontoRetencionIVA              string `json:"monto_retencion_iva"`
+	MontoRetencionOtrosImpuestos   string `json:"monto_retencion_otros_impuestos"`
+	NumeroPedimentoVinculado       string `json:"numero_pedimento_vinculado"`
+	ClavePedimentoVinculado        string `json:"clave_pedimento_vinculado"`
+	ClavePagoPedimentoVinculado    string `json:"clave_pago_pedimento_vinculado"`
+	MontoIVAPedimento              string `json:"monto_iva_pedimento"`
+	OtrosImpuestosPagadosPedimento string `json:"otros_impuestos_pagados_pedimento"`
+	FechaFolioFiscalVinculado      string `json:"fecha_folio_fiscal_vinculado"`
+	Mes                            string `json:"mes"`
+	MontoTotalErogaciones          string `json:"monto_total_erogaciones"`
+}
+
+// ActividadGCEH representa una actividad de una erogación de Gastos de Hidrocarburos 1.0.
+type ActividadGCEH struct {
+	ActividadRelacionada string             `json:"actividad_relacionada"`
+	SubActividades       []SubActividadGCEH `json:"sub_actividades"`
+}
+
+// SubActividadGCEH representa una subactividad de una erogación de Gastos de Hidrocarburos 1.0.
+type SubActividadGCEH struct {
+	SubActividadRelacionada string   `json:"sub_actividad_relacionada"`
+	Tareas                  []string `json:"tareas"`
+}
+
+// CentroCostosGCEH representa un centro de costos de una erogación de Gastos de Hidrocarburos 1.0.
+type CentroCostosGCEH struct {
+	Campo       string           `json:"campo"`
+	Yacimientos []YacimientoGCEH `json:"yacimientos"`
+}
+
+// YacimientoGCEH representa un yacimiento de un centro de costos de Gastos de Hidrocarburos 1.0.
+type YacimientoGCEH struct {
+	Yacimiento string   `json:"yacimiento"`
+	Pozos      []string `json:"pozos"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// IEDU10Data representa los datos de un complemento concepto Instituciones Educativas Privadas 1.0.
+type IEDU10Data struct {
+	Version        string `json:"version"`
+	NombreAlumno   string `json:"nombre_alumno"`
+	CURP           string `json:"curp"`
+	NivelEducativo string `json:"nivel_educativo"`
+	AutRVOE        string `json:"aut_rvoe"`
+	RfcPago        string `json:"rfc_pago"`
+}
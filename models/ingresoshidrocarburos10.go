@@ -0,0 +1,17 @@
+package models
+
+// IngresosHidrocarburos10Data representa los datos de un complemento Ingresos Atribuibles a los Integrantes de un Consorcio (Hidrocarburos) 1.0.
+type IngresosHidrocarburos10Data struct {
+	Version                        string                     `json:"version"`
+	NumeroContrato                 string                     `json:"numero_contrato"`
+	ContraprestacionPagadaOperador string                     `json:"contraprestacion_pagada_operador"`
+	Porcentaje                     string                     `json:"porcentaje"`
+	DocumentosRelacionados         []DocumentoRelacionadoIEEH `json:"documentos_relacionados"`
+}
+
+// DocumentoRelacionadoIEEH representa un documento relacionado de un complemento Ingresos Hidrocarburos 1.0.
+type DocumentoRelacionadoIEEH struct {
+	FolioFiscalVinculado      string `json:"folio_fiscal_vinculado"`
+	FechaFolioFiscalVinculado string `json:"fecha_folio_fiscal_vinculado"`
+	Mes                       string `json:"mes"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// LeyendasFiscales10Data representa los datos de un complemento Leyendas Fiscales 1.0.
+type LeyendasFiscales10Data struct {
+	Version  string    `json:"version"`
+	Leyendas []Leyenda `json:"leyendas"`
+}
+
+// Leyenda representa una leyenda de un complemento Leyendas Fiscales 1.0.
+type Leyenda struct {
+	DisposicionFiscal string `json:"disposicion_fiscal"`
+	Norma             string `json:"norma"`
+	TextoLeyenda      string `json:"texto_leyenda"`
+}
@@ -0,0 +1,64 @@
+package models
+
+// Pagos10Data es la estructura de datos para el complemento Pagos version 1.0 (CFDI 3.3).
+type Pagos10Data struct {
+	Version string   `json:"version"`
+	Pagos   []Pago10 `json:"pago"`
+}
+
+// Pago10 es la estructura de datos para un pago individual en Pagos 1.0.
+type Pago10 struct {
+	FechaPago        string               `json:"fecha_pago"`
+	FormaDePagoP     string               `json:"forma_de_pago_p"`
+	MonedaP          string               `json:"moneda_p"`
+	TipoCambioP      string               `json:"tipo_cambio_p"`
+	Monto            string               `json:"monto"`
+	NumOperacion     string               `json:"num_operacion"`
+	RfcEmisorCtaOrd  string               `json:"rfc_emisor_cta_ord"`
+	NomBancoOrdExt   string               `json:"nom_banco_ord_ext"`
+	CtaOrdenante     string               `json:"cta_ordenante"`
+	RfcEmisorCtaBen  string               `json:"rfc_emisor_cta_ben"`
+	CtaBeneficiario  string               `json:"cta_beneficiario"`
+	TipoCadPago      string               `json:"tipo_cad_pago"`
+	CertPago         string               `json:"cert_pago"`
+	CadPago          string               `json:"cad_pago"`
+	SelloPago        string               `json:"sello_pago"`
+	DoctoRelacionado []DoctoRelacionado10 `json:"docto_relacionado"`
+	Impuestos        []ImpuestosPago10    `json:"impuestos"`
+}
+
+// DoctoRelacionado10 es la estructura de datos para un documento relacionado en Pagos 1.0.
+type DoctoRelacionado10 struct {
+	IdDocumento      string `json:"id_documento"`
+	Serie            string `json:"serie"`
+	Folio            string `json:"folio"`
+	MonedaDR         string `json:"moneda_dr"`
+	TipoCambioDR     string `json:"tipo_cambio_dr"`
+	MetodoDePagoDR   string `json:"metodo_de_pago_dr"`
+	NumParcialidad   string `json:"num_parcialidad"`
+	ImpSaldoAnt      string `json:"imp_saldo_ant"`
+	ImpPagado        string `json:"imp_pagado"`
+	ImpSaldoInsoluto string `json:"imp_saldo_insoluto"`
+}
+
+// ImpuestosPago10 es la estructura de datos para los impuestos de un pago en Pagos 1.0.
+type ImpuestosPago10 struct {
+	TotalImpuestosRetenidos   string        `json:"total_impuestos_retenidos"`
+	TotalImpuestosTrasladados string        `json:"total_impuestos_trasladados"`
+	Retenciones               []Retencion10 `json:"retenciones"`
+	Traslados                 []Traslado10  `json:"traslados"`
+}
+
+// Retencion10 es la estructura de datos para una retención de un pago en Pagos 1.0.
+type Retencion10 struct {
+	Impuesto string `json:"impuesto"`
+	Importe  string `json:"importe"`
+}
+
+// Traslado10 es la estructura de datos para un traslado de un pago en Pagos 1.0.
+type Traslado10 struct {
+	Impuesto   string `json:"impuesto"`
+	TipoFactor string `json:"tipo_factor"`
+	TasaOCuota string `json:"tasa_o_cuota"`
+	Importe    string `json:"importe"`
+}
@@ -5,9 +5,13 @@ type Pagos20Data struct {
 	Version string    `json:"version"`
 	Totales Totales20 `json:"totales"`
 	Pagos   []Pago20  `json:"pago"`
+	// ResumenImpuestos agrega el ResumenImpuestosP de todos los pagos, en MXN.
+	ResumenImpuestos []ResumenImpuestoP `json:"resumen_impuestos,omitempty"`
 }
 
 // Totales20 es la estructura de datos para la sección de totales en Pagos 2.0.
+// En los datos normalizados desde Pagos 1.0 los campos TotalTrasladosBase* quedan con EmptyChar,
+// aun con SafeNumerics, porque Pagos 1.0 no registra la base de los traslados.
 type Totales20 struct {
 	TotalRetencionesIVA         string `json:"total_retenciones_iva"`
 	TotalRetencionesISR         string `json:"total_retenciones_isr"`
@@ -41,6 +45,8 @@ type Pago20 struct {
 	SelloPago        string               `json:"sello_pago"`
 	DoctoRelacionado []DoctoRelacionado20 `json:"docto_relacionado"`
 	ImpuestosP       []ImpuestosP         `json:"impuestos_p"`
+	// ResumenImpuestosP agrupa los ImpuestosP del pago por impuesto y tasa, en MXN.
+	ResumenImpuestosP []ResumenImpuestoP `json:"resumen_impuestos_p,omitempty"`
 }
 
 // DoctoRelacionado20 es la estructura de datos para un documento relacionado en Pagos 2.0.
@@ -93,3 +99,15 @@ type TrasladoP struct {
 	TasaOCuotaP string `json:"tasa_o_cuota_p"`
 	ImporteP    string `json:"importe_p"`
 }
+
+// ResumenImpuestoP es el total de un impuesto de los pagos agrupado por tipo, impuesto, tipo factor y tasa o cuota.
+// Base e Importe estan en MXN, convertidos con el TipoCambioP de cada pago. Base queda vacia en las
+// retenciones y en los traslados sin BaseP, como los normalizados desde Pagos 1.0.
+type ResumenImpuestoP struct {
+	Tipo       string `json:"tipo"`
+	Impuesto   string `json:"impuesto"`
+	TipoFactor string `json:"tipo_factor"`
+	TasaOCuota string `json:"tasa_o_cuota"`
+	Base       string `json:"base"`
+	Importe    string `json:"importe"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// ServicioParcial10Data representa los datos de un complemento Servicios Parciales de Construcción 1.0.
+type ServicioParcial10Data struct {
+	Version       string   `json:"version"`
+	NumPerLicoAut string   `json:"num_per_lic_o_aut"`
+	Inmueble      Inmueble `json:"inmueble"`
+}
+
+// Inmueble representa el inmueble de un complemento Servicios Parciales de Construcción 1.0.
+type Inmueble struct {
+	Calle        string `json:"calle"`
+	NoExterior   string `json:"no_exterior"`
+	NoInterior   string `json:"no_interior"`
+	Colonia      string `json:"colonia"`
+	Localidad    string `json:"localidad"`
+	Referencia   string `json:"referencia"`
+	Municipio    string `json:"municipio"`
+	Estado       string `json:"estado"`
+	CodigoPostal string `json:"codigo_postal"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// TuristaPasajeroExtranjero10Data representa los datos de un complemento Turista Pasajero Extranjero 1.0.
+type TuristaPasajeroExtranjero10Data struct {
+	Version         string        `json:"version"`
+	FechadeTransito string        `json:"fecha_de_transito"`
+	TipoTransito    string        `json:"tipo_transito"`
+	DatosTransito   DatosTransito `json:"datos_transito"`
+}
+
+// DatosTransito representa los datos del tránsito de un complemento Turista Pasajero Extranjero 1.0.
+type DatosTransito struct {
+	Via               string `json:"via"`
+	TipoID            string `json:"tipo_id"`
+	NumeroID          string `json:"numero_id"`
+	Nacionalidad      string `json:"nacionalidad"`
+	EmpresaTransporte string `json:"empresa_transporte"`
+	IDTransporte      string `json:"id_transporte"`
+}
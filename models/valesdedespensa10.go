@@ -0,0 +1,22 @@
+package models
+
+// ValesDeDespensa10Data representa los datos de un complemento Vales de Despensa 1.0.
+type ValesDeDespensa10Data struct {
+	Version          string                    `json:"version"`
+	TipoOperacion    string                    `json:"tipo_operacion"`
+	RegistroPatronal string                    `json:"registro_patronal"`
+	NumeroDeCuenta   string                    `json:"numero_de_cuenta"`
+	Total            string                    `json:"total"`
+	Conceptos        []ValesDeDespensaConcepto `json:"conceptos"`
+}
+
+// ValesDeDespensaConcepto representa un concepto de un complemento Vales de Despensa 1.0.
+type ValesDeDespensaConcepto struct {
+	Identificador      string `json:"identificador"`
+	Fecha              string `json:"fecha"`
+	Rfc                string `json:"rfc"`
+	Curp               string `json:"curp"`
+	Nombre             string `json:"nombre"`
+	NumSeguridadSocial string `json:"num_seguridad_social"`
+	Importe            string `json:"importe"`
+}
@@ -0,0 +1,213 @@
+// Package money convierte importes de CFDI parseados a MXN o a la moneda del documento usando sus propios tipos de cambio.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// MXN es la clave de la moneda nacional.
+const MXN = "MXN"
+
+// ErrMissingRate se retorna cuando el documento no tiene tipo de cambio y la tabla de tipos de cambio no lo resuelve.
+var ErrMissingRate = errors.New("exchange rate not available")
+
+// ErrInvalidAmount se retorna cuando un importe no es un decimal valido.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// decimalsByCurrency contiene las monedas del catalogo c_Moneda que no usan dos decimales.
+var decimalsByCurrency = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0,
+	"XPF": 0, "XXX": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Decimals returns the number of decimals of a currency according to the c_Moneda catalog.
+func Decimals(moneda string) int32 {
+	if d, ok := decimalsByCurrency[strings.ToUpper(moneda)]; ok {
+		return d
+	}
+	return 2
+}
+
+// Round rounds an amount to the decimals of the currency, half away from zero as the SAT does.
+func Round(amount decimal.Decimal, moneda string) decimal.Decimal {
+	return amount.Round(Decimals(moneda))
+}
+
+// RateTable resuelve el tipo de cambio, en MXN por unidad de moneda, para una fecha "2006-01-02".
+type RateTable interface {
+	Rate(moneda, fecha string) (decimal.Decimal, bool)
+}
+
+// Rates es una tabla de tipos de cambio fijos por moneda, sin importar la fecha.
+type Rates map[string]decimal.Decimal
+
+// Rate returns the rate of the currency.
+func (r Rates) Rate(moneda, _ string) (decimal.Decimal, bool) {
+	rate, ok := r[strings.ToUpper(moneda)]
+	return rate, ok
+}
+
+// DailyRates es una tabla de tipos de cambio por moneda y fecha "2006-01-02", p. ej. los publicados en el DOF.
+type DailyRates map[string]map[string]decimal.Decimal
+
+// Rate returns the rate of the currency for the date.
+func (r DailyRates) Rate(moneda, fecha string) (decimal.Decimal, bool) {
+	rate, ok := r[strings.ToUpper(moneda)][fecha]
+	return rate, ok
+}
+
+// Converter convierte importes usando los tipos de cambio del documento.
+// La tabla de tipos de cambio, opcional, solo se usa cuando el documento no los tiene.
+type Converter struct {
+	rates RateTable
+}
+
+// NewConverter creates a Converter with an optional rate table, nil to use only the document rates.
+func NewConverter(rates RateTable) *Converter {
+	return &Converter{rates: rates}
+}
+
+// DocumentRate returns the MXN rate of the document currency from CFDI40.TipoCambio.
+func (c *Converter) DocumentRate(doc models.CFDI40Data) (decimal.Decimal, error) {
+	return c.rate(doc.CFDI40.Moneda, doc.CFDI40.TipoCambio, doc.CFDI40.Fecha)
+}
+
+// DocumentToMXN converts an amount in the document currency to MXN.
+func (c *Converter) DocumentToMXN(doc models.CFDI40Data, amount string) (decimal.Decimal, error) {
+	value, err := parseAmount(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	rate, err := c.DocumentRate(doc)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Round(value.Mul(rate), MXN), nil
+}
+
+// MXNToDocument converts an amount in MXN to the document currency.
+func (c *Converter) MXNToDocument(doc models.CFDI40Data, amount string) (decimal.Decimal, error) {
+	value, err := parseAmount(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	rate, err := c.DocumentRate(doc)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Round(value.Div(rate), doc.CFDI40.Moneda), nil
+}
+
+// PagoRate returns the MXN rate of the payment currency from TipoCambioP.
+func (c *Converter) PagoRate(pago models.Pago20) (decimal.Decimal, error) {
+	return c.rate(pago.MonedaP, pago.TipoCambioP, pago.FechaPago)
+}
+
+// PagoToMXN converts an amount in the payment currency (MonedaP) to MXN.
+func (c *Converter) PagoToMXN(pago models.Pago20, amount string) (decimal.Decimal, error) {
+	value, err := parseAmount(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	rate, err := c.PagoRate(pago)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Round(value.Mul(rate), MXN), nil
+}
+
+// DoctoToPago converts an amount in the currency of the related document (MonedaDR) to the payment currency
+// dividing by EquivalenciaDR, the units of MonedaDR per unit of MonedaP.
+func (c *Converter) DoctoToPago(pago models.Pago20, docto models.DoctoRelacionado20, amount string) (decimal.Decimal, error) {
+	value, err := parseAmount(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	equivalencia, err := equivalenciaDR(pago, docto)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Round(value.Div(equivalencia), pago.MonedaP), nil
+}
+
+// DoctoToMXN converts an amount in the currency of the related document (MonedaDR) to MXN,
+// through the payment currency with EquivalenciaDR and TipoCambioP.
+func (c *Converter) DoctoToMXN(pago models.Pago20, docto models.DoctoRelacionado20, amount string) (decimal.Decimal, error) {
+	value, err := parseAmount(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if strings.EqualFold(docto.MonedaDR, MXN) {
+		return Round(value, MXN), nil
+	}
+	equivalencia, err := equivalenciaDR(pago, docto)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	rate, err := c.PagoRate(pago)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return Round(value.Div(equivalencia).Mul(rate), MXN), nil
+}
+
+// rate resolves the MXN rate of a currency from the document value or, when missing, the rate table.
+// A rate of 1 for a currency other than MXN is the SafeNumerics default and is treated as missing
+// when the rate table resolves it.
+func (c *Converter) rate(moneda, tipoCambio, fecha string) (decimal.Decimal, error) {
+	moneda = strings.ToUpper(moneda)
+	if moneda == MXN || moneda == "XXX" {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, err := decimal.NewFromString(tipoCambio)
+	valid := err == nil && rate.IsPositive()
+	if valid && !rate.Equal(decimal.NewFromInt(1)) {
+		return rate, nil
+	}
+
+	if c.rates != nil {
+		if tableRate, ok := c.rates.Rate(moneda, day(fecha)); ok {
+			return tableRate, nil
+		}
+	}
+	if valid {
+		return rate, nil
+	}
+	return decimal.Zero, fmt.Errorf("%s on %s: %w", moneda, day(fecha), ErrMissingRate)
+}
+
+func equivalenciaDR(pago models.Pago20, docto models.DoctoRelacionado20) (decimal.Decimal, error) {
+	if strings.EqualFold(pago.MonedaP, docto.MonedaDR) {
+		return decimal.NewFromInt(1), nil
+	}
+	equivalencia, err := decimal.NewFromString(docto.EquivalenciaDR)
+	if err != nil || !equivalencia.IsPositive() {
+		return decimal.Zero, fmt.Errorf("EquivalenciaDR of %s: %w", docto.IdDocumento, ErrMissingRate)
+	}
+	return equivalencia, nil
+}
+
+func parseAmount(amount string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%q: %w", amount, ErrInvalidAmount)
+	}
+	return value, nil
+}
+
+// day returns the date part of a CFDI date "2006-01-02T15:04:05".
+func day(fecha string) string {
+	if len(fecha) >= 10 {
+		return fecha[:10]
+	}
+	return fecha
+}
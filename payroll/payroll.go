@@ -0,0 +1,290 @@
+// Package payroll acumula los recibos de Nomina 1.2 por empleado y ejercicio, con los totales
+// que se reportan en la constancia anual de sueldos y salarios.
+package payroll
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ErrNoNomina se retorna al agregar un documento sin complemento de Nomina 1.2.
+var ErrNoNomina = errors.New("document has no Nomina 1.2 complement")
+
+// ErrMissingEmployee se retorna cuando el Receptor de la nomina no tiene Curp ni NumEmpleado.
+var ErrMissingEmployee = errors.New("nomina receptor has no Curp or NumEmpleado")
+
+// ErrInvalidFechaPago se retorna cuando la FechaPago de la nomina no permite obtener el ejercicio.
+var ErrInvalidFechaPago = errors.New("nomina FechaPago has no valid year")
+
+const (
+	// DeduccionIMSS es el c_TipoDeduccion de la seguridad social.
+	DeduccionIMSS = "001"
+	// DeduccionISR es el c_TipoDeduccion del ISR retenido.
+	DeduccionISR = "002"
+	// OtroPagoSubsidio es el c_TipoOtroPago del subsidio para el empleo efectivamente entregado.
+	OtroPagoSubsidio = "002"
+	// NominaExtraordinaria es el c_TipoNomina de los pagos extraordinarios, que no cubren dias del periodo.
+	NominaExtraordinaria = "E"
+)
+
+// Key identifica a un empleado de un patron (RfcEmisor) en un ejercicio.
+// RfcEmisor y Curp se normalizan a mayusculas; si el recibo no trae Curp se usa NumEmpleado.
+type Key struct {
+	RfcEmisor   string
+	Curp        string
+	NumEmpleado string
+	Year        string
+}
+
+// Period es un periodo de pago de un recibo de nomina.
+type Period struct {
+	// UUID es el UUID del recibo, vacio si el documento no esta timbrado.
+	UUID             string
+	TipoNomina       string
+	PeriodicidadPago string
+	FechaPago        string
+	FechaInicialPago string
+	FechaFinalPago   string
+	NumDiasPagados   decimal.Decimal
+}
+
+// Summary son los totales de un empleado en un ejercicio.
+type Summary struct {
+	Key
+	RfcReceptor string
+	Nombre      string
+	Receipts    int
+	// Gross es la suma de percepciones (gravadas y exentas) y otros pagos.
+	Gross             decimal.Decimal
+	TotalGravado      decimal.Decimal
+	TotalExento       decimal.Decimal
+	TotalOtrosPagos   decimal.Decimal
+	TotalDeducciones  decimal.Decimal
+	ISRRetenido       decimal.Decimal
+	IMSS              decimal.Decimal
+	SubsidioCausado   decimal.Decimal
+	SubsidioEntregado decimal.Decimal
+	// DiasPagados suma NumDiasPagados de los recibos ordinarios; las nominas extraordinarias no agregan dias.
+	DiasPagados decimal.Decimal
+	// OrigenRecurso es el de la ultima EntidadSNCF del ejercicio, vacio si el emisor no es una entidad SNCF.
+	OrigenRecurso string
+	// MontoRecursoPropio es la suma de EntidadSNCF.MontoRecursoPropio de los recibos con recursos mixtos.
+	MontoRecursoPropio decimal.Decimal
+	// Periods son los periodos pagados, ordenados por FechaInicialPago y FechaPago.
+	Periods []Period
+}
+
+// Aggregator acumula recibos de nomina por empleado y ejercicio, seguro para uso concurrente.
+type Aggregator struct {
+	mu        sync.RWMutex
+	summaries map[Key]*Summary
+	seen      map[string]bool
+}
+
+// New creates an empty Aggregator.
+func New() *Aggregator {
+	return &Aggregator{
+		summaries: map[Key]*Summary{},
+		seen:      map[string]bool{},
+	}
+}
+
+// Add accumulates every Nomina 1.2 complement of doc.
+// A document whose TFD UUID was already added is ignored, so the same receipt
+// can be fed from several sources without double counting.
+func (a *Aggregator) Add(doc models.CFDI40Data) error {
+	if len(doc.Nomina12) == 0 {
+		return ErrNoNomina
+	}
+
+	uuid := ""
+	if len(doc.TFD11) > 0 {
+		uuid = strings.ToUpper(doc.TFD11[0].UUID)
+	}
+
+	keys := make([]Key, 0, len(doc.Nomina12))
+	for _, nomina := range doc.Nomina12 {
+		key, err := keyOf(doc.CFDI40.Emisor.RFC, nomina)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if uuid != "" {
+		if a.seen[uuid] {
+			return nil
+		}
+		a.seen[uuid] = true
+	}
+
+	for i, nomina := range doc.Nomina12 {
+		summary, ok := a.summaries[keys[i]]
+		if !ok {
+			summary = &Summary{Key: keys[i]}
+			a.summaries[keys[i]] = summary
+		}
+		summary.RfcReceptor = strings.ToUpper(doc.CFDI40.Receptor.RFC)
+		summary.Nombre = doc.CFDI40.Receptor.Nombre
+		summary.add(uuid, nomina)
+	}
+	return nil
+}
+
+// Get returns the summary of the employee identified by curp (or NumEmpleado) with the employer rfcEmisor in year.
+func (a *Aggregator) Get(rfcEmisor, curp, year string) (Summary, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	emisor := strings.ToUpper(strings.TrimSpace(rfcEmisor))
+	id := strings.ToUpper(strings.TrimSpace(curp))
+	for key, summary := range a.summaries {
+		if key.Year != year || key.RfcEmisor != emisor {
+			continue
+		}
+		if key.Curp == id || (key.Curp == "" && key.NumEmpleado == strings.TrimSpace(curp)) {
+			return summary.clone(), true
+		}
+	}
+	return Summary{}, false
+}
+
+// Summaries returns every summary sorted by year, RfcEmisor, Curp and NumEmpleado.
+func (a *Aggregator) Summaries() []Summary {
+	return a.filter(func(*Summary) bool { return true })
+}
+
+// Year returns the summaries of the given year sorted by RfcEmisor, Curp and NumEmpleado.
+func (a *Aggregator) Year(year string) []Summary {
+	return a.filter(func(s *Summary) bool { return s.Year == year })
+}
+
+func (a *Aggregator) filter(keep func(*Summary) bool) []Summary {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var result []Summary
+	for _, summary := range a.summaries {
+		if keep(summary) {
+			result = append(result, summary.clone())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Year != result[j].Year {
+			return result[i].Year < result[j].Year
+		}
+		if result[i].RfcEmisor != result[j].RfcEmisor {
+			return result[i].RfcEmisor < result[j].RfcEmisor
+		}
+		if result[i].Curp != result[j].Curp {
+			return result[i].Curp < result[j].Curp
+		}
+		return result[i].NumEmpleado < result[j].NumEmpleado
+	})
+	return result
+}
+
+func keyOf(rfcEmisor string, nomina models.Nomina12Data) (Key, error) {
+	key := employeeKey(nomina)
+	if key.Curp == "" && key.NumEmpleado == "" {
+		return Key{}, ErrMissingEmployee
+	}
+	key.RfcEmisor = strings.ToUpper(strings.TrimSpace(rfcEmisor))
+	if len(nomina.FechaPago) < 4 {
+		return Key{}, ErrInvalidFechaPago
+	}
+	key.Year = nomina.FechaPago[:4]
+	for _, r := range key.Year {
+		if r < '0' || r > '9' {
+			return Key{}, ErrInvalidFechaPago
+		}
+	}
+	return key, nil
+}
+
+// employeeKey returns the Key of the nomina receptor without the employer and the year.
+// Sin Curp, el NumEmpleado es la unica identidad; con Curp, NumEmpleado
+// no separa al empleado aunque cambie entre recibos.
+func employeeKey(nomina models.Nomina12Data) Key {
+	key := Key{Curp: strings.ToUpper(strings.TrimSpace(nomina.Receptor.Curp))}
+	if key.Curp == "" {
+		key.NumEmpleado = strings.TrimSpace(nomina.Receptor.NumEmpleado)
+	}
+	return key
+}
+
+func (s *Summary) add(uuid string, nomina models.Nomina12Data) {
+	s.Receipts++
+
+	gravado := decimal.Zero
+	exento := decimal.Zero
+	for _, percepcion := range nomina.Percepciones.Percepcion {
+		gravado = gravado.Add(helpers.TryParseDecimal(percepcion.ImporteGravado))
+		exento = exento.Add(helpers.TryParseDecimal(percepcion.ImporteExento))
+	}
+	s.TotalGravado = s.TotalGravado.Add(gravado)
+	s.TotalExento = s.TotalExento.Add(exento)
+
+	otrosPagos := decimal.Zero
+	for _, otroPago := range nomina.OtrosPagos.OtroPago {
+		importe := helpers.TryParseDecimal(otroPago.Importe)
+		otrosPagos = otrosPagos.Add(importe)
+		s.SubsidioCausado = s.SubsidioCausado.Add(helpers.TryParseDecimal(otroPago.SubsidioAlEmpleo.SubsidioCausado))
+		if otroPago.TipoOtroPago == OtroPagoSubsidio {
+			s.SubsidioEntregado = s.SubsidioEntregado.Add(importe)
+		}
+	}
+	s.TotalOtrosPagos = s.TotalOtrosPagos.Add(otrosPagos)
+	s.Gross = s.Gross.Add(gravado).Add(exento).Add(otrosPagos)
+
+	for _, deduccion := range nomina.Deducciones.Deduccion {
+		importe := helpers.TryParseDecimal(deduccion.Importe)
+		s.TotalDeducciones = s.TotalDeducciones.Add(importe)
+		switch deduccion.TipoDeduccion {
+		case DeduccionISR:
+			s.ISRRetenido = s.ISRRetenido.Add(importe)
+		case DeduccionIMSS:
+			s.IMSS = s.IMSS.Add(importe)
+		}
+	}
+
+	if entidad := nomina.Emisor.EntidadSNCF; entidad.OrigenRecurso != "" {
+		s.OrigenRecurso = entidad.OrigenRecurso
+		s.MontoRecursoPropio = s.MontoRecursoPropio.Add(helpers.TryParseDecimal(entidad.MontoRecursoPropio))
+	}
+
+	dias := helpers.TryParseDecimal(nomina.NumDiasPagados)
+	if nomina.TipoNomina != NominaExtraordinaria {
+		s.DiasPagados = s.DiasPagados.Add(dias)
+	}
+	s.Periods = append(s.Periods, Period{
+		UUID:             uuid,
+		TipoNomina:       nomina.TipoNomina,
+		PeriodicidadPago: nomina.Receptor.PeriodicidadPago,
+		FechaPago:        nomina.FechaPago,
+		FechaInicialPago: nomina.FechaInicialPago,
+		FechaFinalPago:   nomina.FechaFinalPago,
+		NumDiasPagados:   dias,
+	})
+	sort.SliceStable(s.Periods, func(i, j int) bool {
+		if s.Periods[i].FechaInicialPago != s.Periods[j].FechaInicialPago {
+			return s.Periods[i].FechaInicialPago < s.Periods[j].FechaInicialPago
+		}
+		return s.Periods[i].FechaPago < s.Periods[j].FechaPago
+	})
+}
+
+func (s *Summary) clone() Summary {
+	out := *s
+	out.Periods = append([]Period(nil), s.Periods...)
+	return out
+}
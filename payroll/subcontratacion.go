@@ -0,0 +1,180 @@
+package payroll
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// DefaultHoursPerDay es la jornada diaria usada cuando no se indica otra.
+var DefaultHoursPerDay = decimal.NewFromInt(8)
+
+var hundred = decimal.NewFromInt(100)
+
+// Assignment es la parte de un recibo de nomina prestada a un cliente (RfcLabora)
+// segun el PorcentajeTiempo de su nodo SubContratacion.
+type Assignment struct {
+	// UUID es el UUID del recibo, vacio si el documento no esta timbrado.
+	UUID             string
+	RfcLabora        string
+	Curp             string
+	NumEmpleado      string
+	FechaPago        string
+	PorcentajeTiempo decimal.Decimal
+	// DiasLaborados es NumDiasPagados por PorcentajeTiempo.
+	DiasLaborados decimal.Decimal
+	// Horas es DiasLaborados por la jornada diaria.
+	Horas decimal.Decimal
+	// Percepciones es la suma de percepciones gravadas y exentas por PorcentajeTiempo.
+	Percepciones decimal.Decimal
+}
+
+// ClientReport es el total de servicios prestados a un cliente de subcontratacion.
+type ClientReport struct {
+	RfcLabora string
+	// Employees son las Curp (o NumEmpleado) que laboraron para el cliente, ordenadas.
+	Employees     []string
+	Receipts      int
+	DiasLaborados decimal.Decimal
+	Horas         decimal.Decimal
+	Percepciones  decimal.Decimal
+	// Assignments son las asignaciones al cliente en el orden de los documentos.
+	Assignments []Assignment
+}
+
+// Assignments splits every Nomina 1.2 complement of doc among its SubContratacion nodes.
+// hoursPerDay is the daily workday used to convert days into hours; zero uses DefaultHoursPerDay.
+// Nominas without SubContratacion and extraordinary nominas, which pay no worked days, produce no assignments.
+func Assignments(doc models.CFDI40Data, hoursPerDay decimal.Decimal) []Assignment {
+	if hoursPerDay.IsZero() {
+		hoursPerDay = DefaultHoursPerDay
+	}
+	uuid := receiptUUID(doc)
+
+	var result []Assignment
+	for _, nomina := range doc.Nomina12 {
+		if nomina.TipoNomina == NominaExtraordinaria {
+			continue
+		}
+		key := employeeKey(nomina)
+		dias := helpers.TryParseDecimal(nomina.NumDiasPagados)
+		percepciones := decimal.Zero
+		for _, percepcion := range nomina.Percepciones.Percepcion {
+			percepciones = percepciones.Add(helpers.TryParseDecimal(percepcion.ImporteGravado)).
+				Add(helpers.TryParseDecimal(percepcion.ImporteExento))
+		}
+
+		for _, sub := range nomina.Receptor.Subcontrataciones {
+			share := helpers.TryParseDecimal(sub.PorcentajeTiempo).Div(hundred)
+			diasLaborados := dias.Mul(share)
+			result = append(result, Assignment{
+				UUID:             uuid,
+				RfcLabora:        strings.ToUpper(strings.TrimSpace(sub.RfcLabora)),
+				Curp:             key.Curp,
+				NumEmpleado:      key.NumEmpleado,
+				FechaPago:        nomina.FechaPago,
+				PorcentajeTiempo: helpers.TryParseDecimal(sub.PorcentajeTiempo),
+				DiasLaborados:    diasLaborados,
+				Horas:            diasLaborados.Mul(hoursPerDay),
+				Percepciones:     percepciones.Mul(share).Round(2),
+			})
+		}
+	}
+	return result
+}
+
+// HoursByRfcLabora returns the hours worked for each RfcLabora across docs.
+// A document whose TFD UUID appears earlier in docs is counted once.
+func HoursByRfcLabora(docs []models.CFDI40Data, hoursPerDay decimal.Decimal) map[string]decimal.Decimal {
+	hours := map[string]decimal.Decimal{}
+	seen := map[string]bool{}
+	for _, doc := range docs {
+		if repeated(doc, seen) {
+			continue
+		}
+		for _, assignment := range Assignments(doc, hoursPerDay) {
+			hours[assignment.RfcLabora] = hours[assignment.RfcLabora].Add(assignment.Horas)
+		}
+	}
+	return hours
+}
+
+// ClientReports groups the assignments of docs by RfcLabora, sorted by RfcLabora.
+// A document whose TFD UUID appears earlier in docs is counted once.
+func ClientReports(docs []models.CFDI40Data, hoursPerDay decimal.Decimal) []ClientReport {
+	reports := map[string]*ClientReport{}
+	employees := map[string]map[string]bool{}
+	receipts := map[string]map[string]bool{}
+	seen := map[string]bool{}
+
+	for i, doc := range docs {
+		if repeated(doc, seen) {
+			continue
+		}
+		for _, assignment := range Assignments(doc, hoursPerDay) {
+			rfc := assignment.RfcLabora
+			report, ok := reports[rfc]
+			if !ok {
+				report = &ClientReport{RfcLabora: rfc}
+				reports[rfc] = report
+				employees[rfc] = map[string]bool{}
+				receipts[rfc] = map[string]bool{}
+			}
+			report.DiasLaborados = report.DiasLaborados.Add(assignment.DiasLaborados)
+			report.Horas = report.Horas.Add(assignment.Horas)
+			report.Percepciones = report.Percepciones.Add(assignment.Percepciones)
+			report.Assignments = append(report.Assignments, assignment)
+
+			employee := assignment.Curp
+			if employee == "" {
+				employee = assignment.NumEmpleado
+			}
+			employees[rfc][employee] = true
+
+			// Los documentos sin timbrar se distinguen por su posicion en docs.
+			receipt := assignment.UUID
+			if receipt == "" {
+				receipt = "#" + strconv.Itoa(i)
+			}
+			receipts[rfc][receipt] = true
+		}
+	}
+
+	result := make([]ClientReport, 0, len(reports))
+	for rfc, report := range reports {
+		for employee := range employees[rfc] {
+			report.Employees = append(report.Employees, employee)
+		}
+		sort.Strings(report.Employees)
+		report.Receipts = len(receipts[rfc])
+		result = append(result, *report)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RfcLabora < result[j].RfcLabora })
+	return result
+}
+
+// receiptUUID returns the TFD UUID of doc, empty if the document is not stamped.
+func receiptUUID(doc models.CFDI40Data) string {
+	if len(doc.TFD11) == 0 {
+		return ""
+	}
+	return strings.ToUpper(doc.TFD11[0].UUID)
+}
+
+// repeated reports whether the TFD UUID of doc is already in seen, and records it.
+// Documents without UUID are never repeated.
+func repeated(doc models.CFDI40Data, seen map[string]bool) bool {
+	uuid := receiptUUID(doc)
+	if uuid == "" {
+		return false
+	}
+	if seen[uuid] {
+		return true
+	}
+	seen[uuid] = true
+	return false
+}
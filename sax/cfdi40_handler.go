@@ -12,10 +12,32 @@ import (
 	"github.com/sucksens/gocfdi-transform/models"
 )
 
+// cfdi40Namespace es el namespace de los elementos del CFDI 4.0.
+const cfdi40Namespace = "http://www.sat.gob.mx/cfd/4"
+
+// cfdi40Elements son los elementos del CFDI 4.0 que lee TransformFromString, con las
+// profundidades a las que aparecen: 0 es el Comprobante y 1 sus hijos directos.
+var cfdi40Elements = map[string][]int{
+	"Comprobante":         {0},
+	"Emisor":              {1},
+	"Receptor":            {1},
+	"Conceptos":           {1},
+	"Concepto":            {2},
+	"Impuestos":           {1, 3},
+	"ComplementoConcepto": {3},
+	"CfdiRelacionados":    {1},
+	"Complemento":         {1},
+	"Addenda":             {1},
+}
+
+// errCFDIVersion se retorna cuando el Comprobante no es de la version 4.0.
+var errCFDIVersion = errors.New("incorrect type of CFDI, this handler only supports CFDI version 4.0")
+
 // CFDI40Handler handles parsing of CFDI 4.0 XML documents.
 type CFDI40Handler struct {
 	config      HandlerConfig
 	complements ComplementRegistry
+	addendas    AddendaRegistry
 }
 
 // NewCFDI40Handler creates a new CFDI40Handler with the given configuration.
@@ -23,6 +45,7 @@ func NewCFDI40Handler(cfg HandlerConfig) *CFDI40Handler {
 	return &CFDI40Handler{
 		config:      cfg,
 		complements: DefaultCFDI40Complements(),
+		addendas:    DefaultAddendaParsers(),
 	}
 }
 
@@ -62,6 +85,88 @@ func (h *CFDI40Handler) UseVentaVehiculos11() *CFDI40Handler {
 	return h
 }
 
+// UseServicioParcial10 enables parsing of Servicios Parciales de Construcción 1.0 complement.
+func (h *CFDI40Handler) UseServicioParcial10() *CFDI40Handler {
+	h.config.ParseServicioParcial10 = true
+	return h
+}
+
+// UseTuristaPasajeroExtranjero10 enables parsing of Turista Pasajero Extranjero 1.0 complement.
+func (h *CFDI40Handler) UseTuristaPasajeroExtranjero10() *CFDI40Handler {
+	h.config.ParseTuristaPasajeroExtranjero10 = true
+	return h
+}
+
+// UseValesDeDespensa10 enables parsing of Vales de Despensa 1.0 complement.
+func (h *CFDI40Handler) UseValesDeDespensa10() *CFDI40Handler {
+	h.config.ParseValesDeDespensa10 = true
+	return h
+}
+
+// UseIngresosHidrocarburos10 enables parsing of Ingresos Hidrocarburos 1.0 complement.
+func (h *CFDI40Handler) UseIngresosHidrocarburos10() *CFDI40Handler {
+	h.config.ParseIngresosHidrocarburos10 = true
+	return h
+}
+
+// UseGastosHidrocarburos10 enables parsing of Gastos Hidrocarburos 1.0 complement.
+func (h *CFDI40Handler) UseGastosHidrocarburos10() *CFDI40Handler {
+	h.config.ParseGastosHidrocarburos10 = true
+	return h
+}
+
+// UseIEDU10 enables parsing of Instituciones Educativas Privadas 1.0 concept complement.
+// Concepts must be enabled with UseConcepts.
+func (h *CFDI40Handler) UseIEDU10() *CFDI40Handler {
+	h.config.ParseIEDU10 = true
+	return h
+}
+
+// UseLeyendasFiscales10 enables parsing of Leyendas Fiscales 1.0 complement.
+func (h *CFDI40Handler) UseLeyendasFiscales10() *CFDI40Handler {
+	h.config.ParseLeyendasFiscales10 = true
+	return h
+}
+
+// UseDonatarias11 enables parsing of Donatarias 1.1 complement.
+func (h *CFDI40Handler) UseDonatarias11() *CFDI40Handler {
+	h.config.ParseDonatarias11 = true
+	return h
+}
+
+// UseDetallista enables parsing of Detallista complement.
+func (h *CFDI40Handler) UseDetallista() *CFDI40Handler {
+	h.config.ParseDetallista = true
+	return h
+}
+
+// UseRawComplements enables capturing the raw XML of the complements not handled by this library.
+func (h *CFDI40Handler) UseRawComplements() *CFDI40Handler {
+	h.config.ParseRawComplements = true
+	return h
+}
+
+// UseAddendas enables extraction of the addenda contents.
+func (h *CFDI40Handler) UseAddendas() *CFDI40Handler {
+	h.config.ParseAddendas = true
+	return h
+}
+
+// UseLenientNamespaces reads the CFDI elements of any namespace by their local name, as
+// some PAC outputs declare them without the CFDI 4.0 namespace. Every element read this
+// way is recorded in Warnings. By default elements of other namespaces are skipped.
+func (h *CFDI40Handler) UseLenientNamespaces() *CFDI40Handler {
+	h.config.LenientNamespaces = true
+	return h
+}
+
+// RegisterAddendaParser registers a parser for the addenda with the given name,
+// either "{namespace}local" or just the local name of the element.
+func (h *CFDI40Handler) RegisterAddendaParser(name string, parser AddendaParser) *CFDI40Handler {
+	h.addendas[name] = parser
+	return h
+}
+
 // TransformFromFile parses a CFDI 4.0 XML file.
 func (h *CFDI40Handler) TransformFromFile(path string) (*models.CFDI40Data, error) {
 	if !strings.HasSuffix(strings.ToLower(path), ".xml") {
@@ -84,6 +189,10 @@ func (h *CFDI40Handler) TransformFromString(xmlStr string) (*models.CFDI40Data,
 	var insideConcepts bool
 	var currentConcept *models.Concepto40
 	var complementNames []string
+	// depth es el numero de elementos abiertos cuyo EndElement leera este ciclo.
+	depth := 0
+	// read[d] son los nombres de los elementos del CFDI 4.0 leidos en la profundidad d bajo el padre actual.
+	var read []map[string]bool
 
 	for {
 		token, err := decoder.Token()
@@ -96,6 +205,29 @@ func (h *CFDI40Handler) TransformFromString(xmlStr string) (*models.CFDI40Data,
 
 		switch se := token.(type) {
 		case xml.StartElement:
+			// The names read under a previous sibling do not apply to this element
+			if len(read) > depth+1 {
+				read = read[:depth+1]
+			}
+			for len(read) <= depth {
+				read = append(read, map[string]bool{})
+			}
+
+			if se.Name.Space != cfdi40Namespace && se.Name != iedu10Element {
+				ok, err := h.foreignElement(se, depth, read[depth][se.Name.Local], decoder, data)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+			} else if se.Name.Space == cfdi40Namespace {
+				read[depth][se.Name.Local] = true
+			}
+
+			// open indica si el EndElement de se queda para este ciclo, es decir,
+			// si ninguna funcion transform consumio el elemento completo.
+			open := true
 			switch se.Name.Local {
 			case "Comprobante":
 				if err := h.transformComprobante(se, data); err != nil {
@@ -116,6 +248,7 @@ func (h *CFDI40Handler) TransformFromString(xmlStr string) (*models.CFDI40Data,
 						return nil, err
 					}
 					insideConcepts = false
+					open = false
 				}
 
 			case "Concepto":
@@ -123,26 +256,45 @@ func (h *CFDI40Handler) TransformFromString(xmlStr string) (*models.CFDI40Data,
 					currentConcept = h.transformConcepto(se)
 				}
 
+			case "instEducativas":
+				if currentConcept != nil && h.config.ParseIEDU10 && se.Name == iedu10Element {
+					iedu, err := NewIEDU10Handler(h.config).ProcessInstEducativasElement(se, decoder)
+					if err == nil && iedu != nil {
+						currentConcept.InstEducativas = iedu
+					}
+					// A version rejected before reading any token leaves the element open
+					open = err != nil
+				}
+
 			case "Impuestos":
 				if !insideConcepts {
 					h.transformImpuestos(se, decoder, data)
+					open = false
 				} else if h.config.ParseConcepts && h.config.ParseConceptsTaxes && currentConcept != nil {
 					h.transformImpuestosConcepto(se, decoder, currentConcept)
+					open = false
 				}
 
 			case "CfdiRelacionados":
 				if h.config.ParseRelatedCFDIs {
 					h.transformCFDIsRelacionados(se, decoder, data)
+					open = false
 				}
 
 			case "Complemento":
-				h.transformComplemento(decoder, data, &complementNames)
+				h.transformComplemento(decoder, xmlStr, data, &complementNames)
+				open = false
 
 			case "Addenda":
-				h.transformAddenda(decoder, data)
+				h.transformAddenda(decoder, xmlStr, data)
+				open = false
+			}
+			if open {
+				depth++
 			}
 
 		case xml.EndElement:
+			depth--
 			switch se.Name.Local {
 			case "Conceptos":
 				insideConcepts = false
@@ -163,10 +315,51 @@ func (h *CFDI40Handler) TransformFromString(xmlStr string) (*models.CFDI40Data,
 	return data, nil
 }
 
+// foreignElement handles a StartElement outside the CFDI 4.0 namespace read by the main loop
+// at the given depth; cfdiRead tells whether a CFDI 4.0 sibling with the same name was already
+// read. It returns true when the element must be read as a CFDI element, which only happens in
+// the lenient mode for a CFDI element name at a depth where that element can appear and that
+// no CFDI 4.0 element read before; otherwise the element is skipped with its children.
+func (h *CFDI40Handler) foreignElement(se xml.StartElement, depth int, cfdiRead bool, decoder *xml.Decoder, data *models.CFDI40Data) (bool, error) {
+	if h.config.LenientNamespaces && expectedDepth(se.Name.Local, depth) {
+		cfdiName := complementKey(xml.Name{Space: cfdi40Namespace, Local: se.Name.Local})
+		if !cfdiRead {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("namespace mismatch: %s read as %s",
+				complementKey(se.Name), cfdiName))
+			return true, nil
+		}
+		// The CFDI 4.0 element already read is kept
+		data.Warnings = append(data.Warnings, fmt.Sprintf("namespace mismatch: %s ignored, %s already read",
+			complementKey(se.Name), cfdiName))
+	}
+
+	if se.Name.Local == "Comprobante" && depth == 0 {
+		// A CFDI of another version keeps the version error
+		if getAttrValue(se, "Version") != "4.0" {
+			return false, errCFDIVersion
+		}
+		return false, fmt.Errorf("incorrect namespace of CFDI, this handler only supports %s", cfdi40Namespace)
+	}
+	if err := decoder.Skip(); err != nil {
+		return false, fmt.Errorf("error parsing XML: %w", err)
+	}
+	return false, nil
+}
+
+// expectedDepth reports whether the CFDI 4.0 element local can appear at depth.
+func expectedDepth(local string, depth int) bool {
+	for _, d := range cfdi40Elements[local] {
+		if d == depth {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *CFDI40Handler) transformComprobante(se xml.StartElement, data *models.CFDI40Data) error {
 	version := getAttrValue(se, "Version")
 	if version != "4.0" {
-		return errors.New("incorrect type of CFDI, this handler only supports CFDI version 4.0")
+		return errCFDIVersion
 	}
 
 	data.CFDI40.Version = version
@@ -229,6 +422,7 @@ func (h *CFDI40Handler) transformImpuestos(se xml.StartElement, decoder *xml.Dec
 	data.CFDI40.Impuestos.TotalImpuestosRetenidos = helpers.GetOrDefault(getAttrValue(se, "TotalImpuestosRetenidos"), h.config.EmptyChar, h.config.SafeNumerics)
 
 	// Parse child elements
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -237,6 +431,11 @@ func (h *CFDI40Handler) transformImpuestos(se xml.StartElement, decoder *xml.Dec
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
 			switch t.Name.Local {
 			case "Traslado":
 				traslado := models.Traslado{
@@ -257,7 +456,7 @@ func (h *CFDI40Handler) transformImpuestos(se xml.StartElement, decoder *xml.Dec
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "Impuestos" {
+			if scope.closes() {
 				return
 			}
 		}
@@ -265,6 +464,7 @@ func (h *CFDI40Handler) transformImpuestos(se xml.StartElement, decoder *xml.Dec
 }
 
 func (h *CFDI40Handler) transformImpuestosConcepto(se xml.StartElement, decoder *xml.Decoder, concept *models.Concepto40) {
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -273,6 +473,11 @@ func (h *CFDI40Handler) transformImpuestosConcepto(se xml.StartElement, decoder
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
 			switch t.Name.Local {
 			case "Traslado":
 				traslado := models.TrasladoConcepto{
@@ -293,7 +498,7 @@ func (h *CFDI40Handler) transformImpuestosConcepto(se xml.StartElement, decoder
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "Impuestos" {
+			if scope.closes() {
 				return
 			}
 		}
@@ -303,6 +508,7 @@ func (h *CFDI40Handler) transformImpuestosConcepto(se xml.StartElement, decoder
 func (h *CFDI40Handler) transformCFDIsRelacionados(se xml.StartElement, decoder *xml.Decoder, data *models.CFDI40Data) {
 	tipoRelacion := getAttrValue(se, "TipoRelacion")
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -311,6 +517,11 @@ func (h *CFDI40Handler) transformCFDIsRelacionados(se xml.StartElement, decoder
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
 			if t.Name.Local == "CfdiRelacionado" {
 				cfdiRel := models.CFDIRelacionado{
 					UUID:         strings.ToUpper(getAttrValue(t, "UUID")),
@@ -320,15 +531,20 @@ func (h *CFDI40Handler) transformCFDIsRelacionados(se xml.StartElement, decoder
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "CfdiRelacionados" {
+			if scope.closes() {
 				return
 			}
 		}
 	}
 }
 
-func (h *CFDI40Handler) transformComplemento(decoder *xml.Decoder, data *models.CFDI40Data, complementNames *[]string) {
+func (h *CFDI40Handler) transformComplemento(decoder *xml.Decoder, xmlStr string, data *models.CFDI40Data, complementNames *[]string) {
+	var current *models.RawComplement
+	var start int64
+	depth := 0
+
 	for {
+		offset := decoder.InputOffset()
 		token, err := decoder.Token()
 		if err != nil {
 			return
@@ -336,62 +552,175 @@ func (h *CFDI40Handler) transformComplemento(decoder *xml.Decoder, data *models.
 
 		switch t := token.(type) {
 		case xml.StartElement:
-			// Record complement name
-			*complementNames = append(*complementNames, t.Name.Local)
-
-			// Handle TFD11
-			if t.Name.Local == "TimbreFiscalDigital" && t.Name.Space == "http://www.sat.gob.mx/TimbreFiscalDigital" {
-				tfd := models.TFD11{
-					Version:          getAttrValue(t, "Version"),
-					NoCertificadoSAT: getAttrValue(t, "NoCertificadoSAT"),
-					UUID:             strings.ToUpper(getAttrValue(t, "UUID")),
-					FechaTimbrado:    getAttrValue(t, "FechaTimbrado"),
-					RfcProvCert:      getAttrValue(t, "RfcProvCertif"),
-					SelloCFD:         helpers.CompactString(h.config.EscDelimiters, getAttrValue(t, "SelloCFD")),
-					SelloSAT:         helpers.CompactString(h.config.EscDelimiters, getAttrValue(t, "SelloSAT")),
-				}
-				data.TFD11 = append(data.TFD11, tfd)
-			}
-
-			// Handle Nomina 1.2
-			if h.config.ParseNomina12 && t.Name.Local == "Nomina" && t.Name.Space == "http://www.sat.gob.mx/nomina12" {
-				nomina12Handler := NewNomina12Handler(h.config)
-				nomina12Data, err := nomina12Handler.ProcessNomina12Element(t, decoder)
-				if err == nil && nomina12Data != nil {
-					data.Nomina12 = append(data.Nomina12, *nomina12Data)
-				}
-			}
+			if depth == 0 {
+				*complementNames = append(*complementNames, t.Name.Local)
+				ref := complementRef(t)
+				data.CFDI40.ComplementosRefs = append(data.CFDI40.ComplementosRefs, ref)
 
-			// Handle Pagos 2.0
-			if h.config.ParsePagos20 && t.Name.Local == "Pagos" && t.Name.Space == "http://www.sat.gob.mx/Pagos20" {
-				pagosHandler := NewPagos20Handler(h.config)
-				pagosData, err := pagosHandler.ProcessPagosElement(t, decoder)
-				if err == nil && pagosData != nil {
-					data.Pagos20 = append(data.Pagos20, *pagosData)
+				if h.transformKnownComplement(t, decoder, data) {
+					continue
 				}
-			}
-
-			// Handle VentaVehiculos 1.1
-			if h.config.ParseVentaVehiculos11 && t.Name.Local == "VentaVehiculos" && t.Name.Space == "http://www.sat.gob.mx/ventavehiculos" {
-				ventaVehiculos11Handler := NewVentaVehiculos11Handler(h.config)
-				ventaVehiculos11Data, err := ventaVehiculos11Handler.ProcessVentaVehiculosElement(t, decoder)
-				if err == nil && ventaVehiculos11Data != nil {
-					data.VentaVehiculos11 = append(data.VentaVehiculos11, *ventaVehiculos11Data)
+				if h.config.ParseRawComplements {
+					start = offset
+					current = &models.RawComplement{ComplementRef: ref}
 				}
 			}
+			depth++
 
 		case xml.EndElement:
-			if t.Name.Local == "Complemento" {
+			if depth == 0 {
 				return
 			}
+			depth--
+			if depth == 0 && current != nil {
+				current.Raw = rawElement(xmlStr, start, decoder.InputOffset())
+				data.RawComplementos = append(data.RawComplementos, *current)
+				current = nil
+			}
+		}
+	}
+}
+
+// transformKnownComplement parses a top-level complement handled by this library.
+// It returns false when the complement is unknown, its parsing is disabled or its version is not supported.
+func (h *CFDI40Handler) transformKnownComplement(t xml.StartElement, decoder *xml.Decoder, data *models.CFDI40Data) bool {
+	offset := decoder.InputOffset()
+	var err error
+
+	switch {
+	// Handle TFD11
+	case t.Name == tfd11Element:
+		tfd := models.TFD11{
+			Version:          getAttrValue(t, "Version"),
+			NoCertificadoSAT: getAttrValue(t, "NoCertificadoSAT"),
+			UUID:             strings.ToUpper(getAttrValue(t, "UUID")),
+			FechaTimbrado:    getAttrValue(t, "FechaTimbrado"),
+			RfcProvCert:      getAttrValue(t, "RfcProvCertif"),
+			SelloCFD:         helpers.CompactString(h.config.EscDelimiters, getAttrValue(t, "SelloCFD")),
+			SelloSAT:         helpers.CompactString(h.config.EscDelimiters, getAttrValue(t, "SelloSAT")),
+		}
+		data.TFD11 = append(data.TFD11, tfd)
+		// The TFD has no children, skip its end element
+		err = decoder.Skip()
+
+	// Handle Nomina 1.2
+	case h.config.ParseNomina12 && t.Name == nomina12Element:
+		var nomina12Data *models.Nomina12Data
+		nomina12Data, err = NewNomina12Handler(h.config).ProcessNomina12Element(t, decoder)
+		if err == nil && nomina12Data != nil {
+			data.Nomina12 = append(data.Nomina12, *nomina12Data)
+		}
+
+	// Handle Pagos 2.0
+	case h.config.ParsePagos20 && t.Name == pagos20Element:
+		var pagosData *models.Pagos20Data
+		pagosData, err = NewPagos20Handler(h.config).ProcessPagosElement(t, decoder)
+		if err == nil && pagosData != nil {
+			data.Pagos20 = append(data.Pagos20, *pagosData)
+		}
+
+	// Handle VentaVehiculos 1.1
+	case h.config.ParseVentaVehiculos11 && t.Name == ventaVehiculos11Element:
+		var ventaVehiculos11Data *models.VentaVehiculos11Data
+		ventaVehiculos11Data, err = NewVentaVehiculos11Handler(h.config).ProcessVentaVehiculosElement(t, decoder)
+		if err == nil && ventaVehiculos11Data != nil {
+			data.VentaVehiculos11 = append(data.VentaVehiculos11, *ventaVehiculos11Data)
+		}
+
+	// Handle Leyendas Fiscales 1.0
+	case h.config.ParseLeyendasFiscales10 && t.Name == leyendasFiscales10Element:
+		var leyendasData *models.LeyendasFiscales10Data
+		leyendasData, err = NewLeyendasFiscales10Handler(h.config).ProcessLeyendasFiscalesElement(t, decoder)
+		if err == nil && leyendasData != nil {
+			data.LeyendasFiscales10 = append(data.LeyendasFiscales10, *leyendasData)
+		}
+
+	// Handle Donatarias 1.1
+	case h.config.ParseDonatarias11 && t.Name == donatarias11Element:
+		var donatariasData *models.Donatarias11Data
+		donatariasData, err = NewDonatarias11Handler(h.config).ProcessDonatariasElement(t, decoder)
+		if err == nil && donatariasData != nil {
+			data.Donatarias11 = append(data.Donatarias11, *donatariasData)
+		}
+
+	// Handle Detallista
+	case h.config.ParseDetallista && t.Name == detallistaElement:
+		var detallistaData *models.DetallistaData
+		detallistaData, err = NewDetallistaHandler(h.config).ProcessDetallistaElement(t, decoder)
+		if err == nil && detallistaData != nil {
+			data.Detallista = append(data.Detallista, *detallistaData)
+		}
+
+	// Handle Servicios Parciales de Construccion 1.0
+	case h.config.ParseServicioParcial10 && t.Name == servicioParcial10Element:
+		var servicioParcialData *models.ServicioParcial10Data
+		servicioParcialData, err = NewServicioParcial10Handler(h.config).ProcessParcialesConstruccionElement(t, decoder)
+		if err == nil && servicioParcialData != nil {
+			data.ServicioParcial10 = append(data.ServicioParcial10, *servicioParcialData)
+		}
+
+	// Handle Turista Pasajero Extranjero 1.0
+	case h.config.ParseTuristaPasajeroExtranjero10 && t.Name == turistaPasajeroExtranjero10Element:
+		var turistaData *models.TuristaPasajeroExtranjero10Data
+		turistaData, err = NewTuristaPasajeroExtranjero10Handler(h.config).ProcessTuristaPasajeroExtranjeroElement(t, decoder)
+		if err == nil && turistaData != nil {
+			data.TuristaPasajeroExtranjero10 = append(data.TuristaPasajeroExtranjero10, *turistaData)
 		}
+
+	// Handle Vales de Despensa 1.0
+	case h.config.ParseValesDeDespensa10 && t.Name == valesDeDespensa10Element:
+		var valesData *models.ValesDeDespensa10Data
+		valesData, err = NewValesDeDespensa10Handler(h.config).ProcessValesDeDespensaElement(t, decoder)
+		if err == nil && valesData != nil {
+			data.ValesDeDespensa10 = append(data.ValesDeDespensa10, *valesData)
+		}
+
+	// Handle Ingresos Hidrocarburos 1.0
+	case h.config.ParseIngresosHidrocarburos10 && t.Name == ingresosHidrocarburos10Element:
+		var ingresosData *models.IngresosHidrocarburos10Data
+		ingresosData, err = NewIngresosHidrocarburos10Handler(h.config).ProcessIngresosHidrocarburosElement(t, decoder)
+		if err == nil && ingresosData != nil {
+			data.IngresosHidrocarburos10 = append(data.IngresosHidrocarburos10, *ingresosData)
+		}
+
+	// Handle Gastos Hidrocarburos 1.0
+	case h.config.ParseGastosHidrocarburos10 && t.Name == gastosHidrocarburos10Element:
+		var gastosData *models.GastosHidrocarburos10Data
+		gastosData, err = NewGastosHidrocarburos10Handler(h.config).ProcessGastosHidrocarburosElement(t, decoder)
+		if err == nil && gastosData != nil {
+			data.GastosHidrocarburos10 = append(data.GastosHidrocarburos10, *gastosData)
+		}
+
+	default:
+		return false
+	}
+
+	// A complement rejected before reading any token, e.g. by its version, is treated as unknown
+	return err == nil || decoder.InputOffset() != offset
+}
+
+// complementRef returns the reference of a top-level complement or addenda element.
+// The version is read from the "Version" attribute or, as some complements declare it, "version".
+func complementRef(se xml.StartElement) models.ComplementRef {
+	version := getAttrValue(se, "Version")
+	if version == "" {
+		version = getAttrValue(se, "version")
+	}
+	return models.ComplementRef{
+		Namespace: se.Name.Space,
+		Local:     se.Name.Local,
+		Version:   version,
 	}
 }
 
-func (h *CFDI40Handler) transformAddenda(decoder *xml.Decoder, data *models.CFDI40Data) {
+func (h *CFDI40Handler) transformAddenda(decoder *xml.Decoder, xmlStr string, data *models.CFDI40Data) {
 	var addendaNames []string
+	var current *models.Addenda
+	var start int64
+	depth := 0
 
 	for {
+		offset := decoder.InputOffset()
 		token, err := decoder.Token()
 		if err != nil {
 			return
@@ -399,15 +728,50 @@ func (h *CFDI40Handler) transformAddenda(decoder *xml.Decoder, data *models.CFDI
 
 		switch t := token.(type) {
 		case xml.StartElement:
-			addendaNames = append(addendaNames, t.Name.Local)
+			if depth == 0 {
+				addendaNames = append(addendaNames, t.Name.Local)
+				data.CFDI40.AddendasRefs = append(data.CFDI40.AddendasRefs, complementRef(t))
+			}
+			if depth == 0 && h.config.ParseAddendas {
+				start = offset
+				current = &models.Addenda{Namespace: t.Name.Space, Local: t.Name.Local}
+			}
+			depth++
 
 		case xml.EndElement:
-			if t.Name.Local == "Addenda" {
+			if depth == 0 {
 				if len(addendaNames) > 0 {
 					data.CFDI40.Addendas = strings.Join(addendaNames, " ")
 				}
 				return
 			}
+			depth--
+			if depth == 0 && current != nil {
+				current.Raw = rawElement(xmlStr, start, decoder.InputOffset())
+				current.Data, current.Error = h.parseAddenda(current)
+				data.Addenda = append(data.Addenda, *current)
+				current = nil
+			}
 		}
 	}
 }
+
+// parseAddenda runs the parser registered for the addenda, falling back to FlattenXML.
+// A parser error is returned as the message recorded on the addenda.
+func (h *CFDI40Handler) parseAddenda(addenda *models.Addenda) (interface{}, string) {
+	parser, ok := h.addendas["{"+addenda.Namespace+"}"+addenda.Local]
+	if !ok {
+		parser, ok = h.addendas[addenda.Local]
+	}
+	if !ok {
+		parser = AddendaParserFunc(func(raw []byte) (interface{}, error) {
+			return FlattenXML(raw)
+		})
+	}
+
+	result, err := parser.ParseAddenda(addenda.Raw)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return result, ""
+}
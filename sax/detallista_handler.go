@@ -0,0 +1,238 @@
+package sax
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// DetallistaHandler handles parsing of the AMECE Detallista complement.
+type DetallistaHandler struct {
+	config HandlerConfig
+}
+
+// detallistaElement es el nombre del elemento raiz del complemento Detallista.
+var detallistaElement = xml.Name{Space: "http://www.sat.gob.mx/detallista", Local: "detallista"}
+
+// NewDetallistaHandler creates a new DetallistaHandler.
+func NewDetallistaHandler(config HandlerConfig) *DetallistaHandler {
+	return &DetallistaHandler{config: config}
+}
+
+// ProcessDetallistaElement processes the detallista element from an existing decoder stream.
+// Most of the values of this complement are element text, so children are matched by their path.
+func (h *DetallistaHandler) ProcessDetallistaElement(se xml.StartElement, decoder *xml.Decoder) (*models.DetallistaData, error) {
+	data := &models.DetallistaData{
+		Type:                     getAttrValue(se, "type"),
+		ContentVersion:           getAttrValue(se, "contentVersion"),
+		DocumentStructureVersion: getAttrValue(se, "documentStructureVersion"),
+		DocumentStatus:           getAttrValue(se, "documentStatus"),
+		SpecialInstructions:      []models.DetallistaSpecialInstruction{},
+		AdditionalInformation:    []models.DetallistaReferenceIdentifier{},
+		Currencies:               []models.DetallistaCurrency{},
+		LineItems:                []models.DetallistaLineItem{},
+		TotalAllowanceCharges:    []models.DetallistaTotalAllowanceCharge{},
+	}
+
+	var path []string
+	var text strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != se.Name.Space {
+				skipElement(decoder)
+				continue
+			}
+			path = append(path, t.Name.Local)
+			text.Reset()
+			h.transformStart(strings.Join(path, "/"), t, data)
+
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.EndElement:
+			// The path tracks the nesting, an EndElement with an empty path closes se
+			if len(path) == 0 {
+				return data, nil
+			}
+			h.transformText(strings.Join(path, "/"), strings.TrimSpace(text.String()), data)
+			text.Reset()
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+func (h *DetallistaHandler) transformStart(path string, se xml.StartElement, data *models.DetallistaData) {
+	switch path {
+	case "specialInstruction":
+		data.SpecialInstructions = append(data.SpecialInstructions, models.DetallistaSpecialInstruction{
+			Code: getAttrValue(se, "code"),
+		})
+	case "AdditionalInformation/referenceIdentification":
+		data.AdditionalInformation = append(data.AdditionalInformation, models.DetallistaReferenceIdentifier{
+			Type: getAttrValue(se, "type"),
+		})
+	case "seller/alternatePartyIdentification":
+		data.SellerAlternateID.Type = getAttrValue(se, "type")
+	case "currency":
+		data.Currencies = append(data.Currencies, models.DetallistaCurrency{
+			CurrencyISOCode: getAttrValue(se, "currencyISOCode"),
+		})
+	case "paymentTerms":
+		data.PaymentTerms.PaymentTermsEvent = getAttrValue(se, "paymentTermsEvent")
+		data.PaymentTerms.PaymentTermsRelationTime = getAttrValue(se, "PaymentTermsRelationTime")
+	case "paymentTerms/netPayment":
+		data.PaymentTerms.NetPaymentTermsType = getAttrValue(se, "netPaymentTermsType")
+	case "paymentTerms/netPayment/paymentTimePeriod/timePeriodDue":
+		data.PaymentTerms.TimePeriod = getAttrValue(se, "timePeriod")
+	case "lineItem":
+		data.LineItems = append(data.LineItems, models.DetallistaLineItem{
+			Type:   getAttrValue(se, "type"),
+			Number: getAttrValue(se, "number"),
+		})
+	case "lineItem/tradeItemDescriptionInformation":
+		if item := lastLineItem(data); item != nil {
+			item.Language = getAttrValue(se, "language")
+		}
+	case "lineItem/invoicedQuantity":
+		if item := lastLineItem(data); item != nil {
+			item.UnitOfMeasure = getAttrValue(se, "unitOfMeasure")
+		}
+	case "TotalAllowanceCharge":
+		data.TotalAllowanceCharges = append(data.TotalAllowanceCharges, models.DetallistaTotalAllowanceCharge{
+			AllowanceOrChargeType: getAttrValue(se, "allowanceOrChargeType"),
+		})
+	}
+}
+
+func (h *DetallistaHandler) transformText(path string, text string, data *models.DetallistaData) {
+	if text == "" {
+		return
+	}
+
+	switch path {
+	case "requestForPaymentIdentification/entityType":
+		data.EntityType = text
+	case "specialInstruction/text":
+		if n := len(data.SpecialInstructions); n > 0 {
+			instruction := &data.SpecialInstructions[n-1]
+			if instruction.Text != "" {
+				instruction.Text += " "
+			}
+			instruction.Text += text
+		}
+	case "orderIdentification/referenceIdentification":
+		data.OrderIdentification.ReferenceIdentification = text
+	case "orderIdentification/ReferenceDate":
+		data.OrderIdentification.ReferenceDate = text
+	case "AdditionalInformation/referenceIdentification":
+		if n := len(data.AdditionalInformation); n > 0 {
+			data.AdditionalInformation[n-1].Value = text
+		}
+	case "DeliveryNote/referenceIdentification":
+		data.DeliveryNote.ReferenceIdentification = text
+	case "DeliveryNote/ReferenceDate":
+		data.DeliveryNote.ReferenceDate = text
+	case "buyer/gln":
+		data.BuyerGLN = text
+	case "buyer/contactInformation/personOrDepartmentName/text":
+		data.BuyerContact = text
+	case "seller/gln":
+		data.SellerGLN = text
+	case "seller/alternatePartyIdentification":
+		data.SellerAlternateID.Value = text
+	case "currency/currencyFunction":
+		if n := len(data.Currencies); n > 0 {
+			data.Currencies[n-1].CurrencyFunction = text
+		}
+	case "currency/rateOfChange":
+		if n := len(data.Currencies); n > 0 {
+			data.Currencies[n-1].RateOfChange = text
+		}
+	case "paymentTerms/netPayment/paymentTimePeriod/timePeriodDue/value":
+		data.PaymentTerms.Value = text
+	case "totalAmount/Amount":
+		data.TotalAmount = text
+	case "TotalAllowanceCharge/specialServicesType":
+		if n := len(data.TotalAllowanceCharges); n > 0 {
+			data.TotalAllowanceCharges[n-1].SpecialServicesType = text
+		}
+	case "TotalAllowanceCharge/Amount":
+		if n := len(data.TotalAllowanceCharges); n > 0 {
+			data.TotalAllowanceCharges[n-1].Amount = text
+		}
+	default:
+		if item := lastLineItem(data); item != nil && strings.HasPrefix(path, "lineItem/") {
+			h.transformLineItemText(strings.TrimPrefix(path, "lineItem/"), text, item)
+		}
+	}
+}
+
+func (h *DetallistaHandler) transformLineItemText(path string, text string, item *models.DetallistaLineItem) {
+	switch path {
+	case "tradeItemIdentification/gtin":
+		item.GTIN = text
+	case "tradeItemDescriptionInformation/longText":
+		item.LongText = text
+	case "invoicedQuantity":
+		item.InvoicedQuantity = text
+	case "grossPrice/Amount":
+		item.GrossPrice = text
+	case "netPrice/Amount":
+		item.NetPrice = text
+	case "totalLineAmount/grossAmount/Amount":
+		item.TotalGrossAmount = text
+	case "totalLineAmount/netAmount/Amount":
+		item.TotalNetAmount = text
+	}
+}
+
+func lastLineItem(data *models.DetallistaData) *models.DetallistaLineItem {
+	if n := len(data.LineItems); n > 0 {
+		return &data.LineItems[n-1]
+	}
+	return nil
+}
+
+// TransformFromBytes parses a Detallista XML byte slice.
+func (h *DetallistaHandler) TransformFromBytes(xmlBytes []byte) (*models.DetallistaData, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Detallista XML string, either the complement alone or a whole document containing it.
+func (h *DetallistaHandler) TransformFromString(xmlString string) (*models.DetallistaData, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *DetallistaHandler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *DetallistaHandler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessDetallistaElement.
+func (h *DetallistaHandler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessDetallistaElement(se, decoder))
+}
+
+// TransformFromReader parses a Detallista XML document read from r.
+func (h *DetallistaHandler) TransformFromReader(r io.Reader) (*models.DetallistaData, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, detallistaElement)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessDetallistaElement(se, decoder)
+}
@@ -0,0 +1,78 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// Donatarias11Handler handles parsing of Donatarias 1.1 complement.
+type Donatarias11Handler struct {
+	config HandlerConfig
+}
+
+// donatarias11Element es el nombre del elemento raiz del complemento Donatarias 1.1.
+var donatarias11Element = xml.Name{Space: "http://www.sat.gob.mx/donat", Local: "Donatarias"}
+
+// NewDonatarias11Handler creates a new Donatarias11Handler.
+func NewDonatarias11Handler(config HandlerConfig) *Donatarias11Handler {
+	return &Donatarias11Handler{config: config}
+}
+
+// ProcessDonatariasElement processes the Donatarias element from an existing decoder stream.
+func (h *Donatarias11Handler) ProcessDonatariasElement(se xml.StartElement, decoder *xml.Decoder) (*models.Donatarias11Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "version"))
+	if version != "1.1" {
+		return nil, errors.New("incorrect type of Donatarias, this handler only supports Donatarias version 1.1")
+	}
+	data := &models.Donatarias11Data{
+		Version:           version,
+		NoAutorizacion:    getAttrValue(se, "noAutorizacion"),
+		FechaAutorizacion: getAttrValue(se, "fechaAutorizacion"),
+		Leyenda:           getAttrValue(se, "leyenda"),
+	}
+
+	// Donatarias has no children, skip until its end element
+	if err := decoder.Skip(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// TransformFromBytes parses a Donatarias 1.1 XML byte slice.
+func (h *Donatarias11Handler) TransformFromBytes(xmlBytes []byte) (*models.Donatarias11Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Donatarias 1.1 XML string, either the complement alone or a whole document containing it.
+func (h *Donatarias11Handler) TransformFromString(xmlString string) (*models.Donatarias11Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *Donatarias11Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *Donatarias11Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessDonatariasElement.
+func (h *Donatarias11Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessDonatariasElement(se, decoder))
+}
+
+// TransformFromReader parses a Donatarias 1.1 XML document read from r.
+func (h *Donatarias11Handler) TransformFromReader(r io.Reader) (*models.Donatarias11Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, donatarias11Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessDonatariasElement(se, decoder)
+}
@@ -0,0 +1,65 @@
+package sax
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FlattenXML flattens an XML fragment into a map keyed by an XPath-like path.
+// Attributes are keyed as "/root/child/@Attr" and text content as "/root/child".
+// Repeated sibling elements get a 1-based index from the second occurrence on: "/root/item", "/root/item[2]".
+func FlattenXML(raw []byte) (map[string]string, error) {
+	result := map[string]string{}
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	decoder.Strict = false
+
+	type frame struct {
+		path     string
+		text     strings.Builder
+		children map[string]int
+	}
+	stack := []*frame{{children: map[string]int{}}}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			parent.children[t.Name.Local]++
+			path := parent.path + "/" + t.Name.Local
+			if n := parent.children[t.Name.Local]; n > 1 {
+				path += "[" + strconv.Itoa(n) + "]"
+			}
+
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				result[path+"/@"+attr.Name.Local] = attr.Value
+			}
+			stack = append(stack, &frame{path: path, children: map[string]int{}})
+
+		case xml.CharData:
+			stack[len(stack)-1].text.Write(t)
+
+		case xml.EndElement:
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if text := strings.TrimSpace(current.text.String()); text != "" {
+				result[current.path] = text
+			}
+		}
+	}
+
+	return result, nil
+}
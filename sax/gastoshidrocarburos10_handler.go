@@ -0,0 +1,265 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// GastosHidrocarburos10Handler handles parsing of Gastos Hidrocarburos 1.0 complement.
+type GastosHidrocarburos10Handler struct {
+	config HandlerConfig
+}
+
+// gastosHidrocarburos10Element es el nombre del elemento raiz del complemento Gastos Hidrocarburos 1.0.
+var gastosHidrocarburos10Element = xml.Name{Space: "http://www.sat.gob.mx/GastosHidrocarburos10", Local: "GastosHidrocarburos"}
+
+// NewGastosHidrocarburos10Handler creates a new GastosHidrocarburos10Handler.
+func NewGastosHidrocarburos10Handler(config HandlerConfig) *GastosHidrocarburos10Handler {
+	return &GastosHidrocarburos10Handler{config: config}
+}
+
+// ProcessGastosHidrocarburosElement processes the GastosHidrocarburos element from an existing decoder stream.
+func (h *GastosHidrocarburos10Handler) ProcessGastosHidrocarburosElement(se xml.StartElement, decoder *xml.Decoder) (*models.GastosHidrocarburos10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "Version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Gastos Hidrocarburos, this handler only supports version 1.0")
+	}
+	data := &models.GastosHidrocarburos10Data{
+		Version:         version,
+		NumeroContrato:  getAttrValue(se, "NumeroContrato"),
+		AreaContractual: getAttrValueOrDefault(se, "AreaContractual", h.config.EmptyChar),
+		Erogaciones:     []models.Erogacion{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			if t.Name.Local == "Erogacion" {
+				data.Erogaciones = append(data.Erogaciones, h.transformErogacion(t, decoder))
+			} else {
+				scope.enter()
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+func (h *GastosHidrocarburos10Handler) transformErogacion(se xml.StartElement, decoder *xml.Decoder) models.Erogacion {
+	erogacion := models.Erogacion{
+		TipoErogacion:          getAttrValue(se, "TipoErogacion"),
+		MontocuErogacion:       getAttrValue(se, "MontocuErogacion"),
+		Porcentaje:             getAttrValue(se, "Porcentaje"),
+		DocumentosRelacionados: []models.DocumentoRelacionadoGCEH{},
+		Actividades:            []models.ActividadGCEH{},
+		CentroCostos:           []models.CentroCostosGCEH{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return erogacion
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			switch t.Name.Local {
+			case "DocumentoRelacionado":
+				erogacion.DocumentosRelacionados = append(erogacion.DocumentosRelacionados, h.transformDocumentoRelacionado(t))
+				scope.enter()
+			case "Actividades":
+				erogacion.Actividades = append(erogacion.Actividades, h.transformActividades(t, decoder))
+			case "CentroCostos":
+				erogacion.CentroCostos = append(erogacion.CentroCostos, h.transformCentroCostos(t, decoder))
+			default:
+				scope.enter()
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return erogacion
+			}
+		}
+	}
+}
+
+func (h *GastosHidrocarburos10Handler) transformDocumentoRelacionado(se xml.StartElement) models.DocumentoRelacionadoGCEH {
+	numeric := func(name string) string {
+		return helpers.GetOrDefault(getAttrValue(se, name), h.config.EmptyChar, h.config.SafeNumerics)
+	}
+	return models.DocumentoRelacionadoGCEH{
+		OrigenErogacion:                getAttrValue(se, "OrigenErogacion"),
+		FolioFiscalVinculado:           strings.ToUpper(getAttrValueOrDefault(se, "FolioFiscalVinculado", h.config.EmptyChar)),
+		RFCProveedor:                   getAttrValueOrDefault(se, "RFCProveedor", h.config.EmptyChar),
+		MontoTotalIVA:                  numeric("MontoTotalIVA"),
+		MontoRetencionISR:              numeric("MontoRetencionISR"),
+		MontoRetencionIVA:              numeric("MontoRetencionIVA"),
+		MontoRetencionOtrosImpuestos:   numeric("MontoRetencionOtrosImpuestos"),
+		NumeroPedimentoVinculado:       getAttrValueOrDefault(se, "NumeroPedimentoVinculado", h.config.EmptyChar),
+		ClavePedimentoVinculado:        getAttrValueOrDefault(se, "ClavePedimentoVinculado", h.config.EmptyChar),
+		ClavePagoPedimentoVinculado:    getAttrValueOrDefault(se, "ClavePagoPedimentoVinculado", h.config.EmptyChar),
+		MontoIVAPedimento:              numeric("MontoIVAPedimento"),
+		OtrosImpuestosPagadosPedimento: numeric("OtrosImpuestosPagadosPedimento"),
+		FechaFolioFiscalVinculado:      getAttrValue(se, "FechaFolioFiscalVinculado"),
+		Mes:                            getAttrValue(se, "Mes"),
+		MontoTotalErogaciones:          getAttrValue(se, "MontoTotalErogaciones"),
+	}
+}
+
+func (h *GastosHidrocarburos10Handler) transformActividades(se xml.StartElement, decoder *xml.Decoder) models.ActividadGCEH {
+	actividad := models.ActividadGCEH{
+		ActividadRelacionada: getAttrValueOrDefault(se, "ActividadRelacionada", h.config.EmptyChar),
+		SubActividades:       []models.SubActividadGCEH{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return actividad
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			if t.Name.Local == "SubActividades" {
+				actividad.SubActividades = append(actividad.SubActividades, h.transformSubActividades(t, decoder))
+			} else {
+				scope.enter()
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return actividad
+			}
+		}
+	}
+}
+
+func (h *GastosHidrocarburos10Handler) transformSubActividades(se xml.StartElement, decoder *xml.Decoder) models.SubActividadGCEH {
+	subActividad := models.SubActividadGCEH{
+		SubActividadRelacionada: getAttrValueOrDefault(se, "SubActividadRelacionada", h.config.EmptyChar),
+		Tareas:                  []string{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return subActividad
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "Tareas" {
+				subActividad.Tareas = append(subActividad.Tareas, getAttrValue(t, "TareaRelacionada"))
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return subActividad
+			}
+		}
+	}
+}
+
+func (h *GastosHidrocarburos10Handler) transformCentroCostos(se xml.StartElement, decoder *xml.Decoder) models.CentroCostosGCEH {
+	centro := models.CentroCostosGCEH{
+		Campo:       getAttrValue(se, "Campo"),
+		Yacimientos: []models.YacimientoGCEH{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return centro
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			switch t.Name.Local {
+			case "Yacimientos":
+				centro.Yacimientos = append(centro.Yacimientos, models.YacimientoGCEH{
+					Yacimiento: getAttrValue(t, "Yacimiento"),
+					Pozos:      []string{},
+				})
+			case "Pozos":
+				if n := len(centro.Yacimientos); n > 0 {
+					centro.Yacimientos[n-1].Pozos = append(centro.Yacimientos[n-1].Pozos, getAttrValue(t, "Pozo"))
+				}
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return centro
+			}
+		}
+	}
+}
+
+// TransformFromBytes parses a Gastos Hidrocarburos 1.0 XML byte slice.
+func (h *GastosHidrocarburos10Handler) TransformFromBytes(xmlBytes []byte) (*models.GastosHidrocarburos10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Gastos Hidrocarburos 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *GastosHidrocarburos10Handler) TransformFromString(xmlString string) (*models.GastosHidrocarburos10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *GastosHidrocarburos10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *GastosHidrocarburos10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessGastosHidrocarburosElement.
+func (h *GastosHidrocarburos10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessGastosHidrocarburosElement(se, decoder))
+}
+
+// TransformFromReader parses a Gastos Hidrocarburos 1.0 XML document read from r.
+func (h *GastosHidrocarburos10Handler) TransformFromReader(r io.Reader) (*models.GastosHidrocarburos10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, gastosHidrocarburos10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessGastosHidrocarburosElement(se, decoder)
+}
@@ -3,35 +3,61 @@ package sax
 
 import (
 	"encoding/xml"
+	"fmt"
+	"io"
 
 	"github.com/sucksens/gocfdi-transform/models"
 )
 
 // HandlerConfig contiene la configuración para el manejador SAX.
 type HandlerConfig struct {
-	EmptyChar             string
-	SafeNumerics          bool
-	EscDelimiters         string
-	ParseConcepts         bool
-	ParseRelatedCFDIs     bool
-	ParseConceptsTaxes    bool
-	ParsePagos20          bool
-	ParseVentaVehiculos11 bool
-	ParseNomina12         bool
+	EmptyChar                        string
+	SafeNumerics                     bool
+	EscDelimiters                    string
+	ParseConcepts                    bool
+	ParseRelatedCFDIs                bool
+	ParseConceptsTaxes               bool
+	ParsePagos20                     bool
+	ParseVentaVehiculos11            bool
+	ParseNomina12                    bool
+	ParseLeyendasFiscales10          bool
+	ParseDonatarias11                bool
+	ParseDetallista                  bool
+	ParseIEDU10                      bool
+	ParseServicioParcial10           bool
+	ParseTuristaPasajeroExtranjero10 bool
+	ParseValesDeDespensa10           bool
+	ParseIngresosHidrocarburos10     bool
+	ParseGastosHidrocarburos10       bool
+	ParseRawComplements              bool
+	ParseAddendas                    bool
+	LenientNamespaces                bool
 }
 
 // NewDefaultConfig retorna una configuración por defecto para el manejador SAX.
 func NewDefaultConfig() HandlerConfig {
 	return HandlerConfig{
-		EmptyChar:             "",
-		SafeNumerics:          false,
-		EscDelimiters:         "",
-		ParseConcepts:         false,
-		ParseRelatedCFDIs:     false,
-		ParseConceptsTaxes:    false,
-		ParsePagos20:          false,
-		ParseVentaVehiculos11: false,
-		ParseNomina12:         false,
+		EmptyChar:                        "",
+		SafeNumerics:                     false,
+		EscDelimiters:                    "",
+		ParseConcepts:                    false,
+		ParseRelatedCFDIs:                false,
+		ParseConceptsTaxes:               false,
+		ParsePagos20:                     false,
+		ParseVentaVehiculos11:            false,
+		ParseNomina12:                    false,
+		ParseLeyendasFiscales10:          false,
+		ParseDonatarias11:                false,
+		ParseDetallista:                  false,
+		ParseIEDU10:                      false,
+		ParseServicioParcial10:           false,
+		ParseTuristaPasajeroExtranjero10: false,
+		ParseValesDeDespensa10:           false,
+		ParseIngresosHidrocarburos10:     false,
+		ParseGastosHidrocarburos10:       false,
+		ParseRawComplements:              false,
+		ParseAddendas:                    false,
+		LenientNamespaces:                false,
 	}
 }
 
@@ -41,22 +67,163 @@ type Handler interface {
 	TransformFromString(xml string) (interface{}, error)
 }
 
+// ComplementHandler es la API comun de los manejadores de complementos. Permite parsear un
+// complemento suelto, p. ej. un fragmento guardado en una columna de base de datos, o leerlo
+// desde un decoder que ya esta posicionado en su elemento raiz.
+//
+// Cada manejador expone ademas TransformFromBytes, TransformFromReader, TransformFromString
+// y su Process*Element, que regresan el modelo concreto en lugar de interface{}.
 type ComplementHandler interface {
-	TransformFromBytes(xml []byte) (interface{}, error)
+	// ParseBytes parses the first root element of the complement found in xml,
+	// either the complement alone or a whole document containing it.
+	ParseBytes(xml []byte) (interface{}, error)
+	// ParseReader is ParseBytes reading from r.
+	ParseReader(r io.Reader) (interface{}, error)
+	// ProcessElement parses the complement whose root StartElement se was just read from
+	// decoder, consuming it through its EndElement.
+	ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error)
 }
 
 type ComplementFactory func(config HandlerConfig) ComplementHandler
 
+// ComplementRegistry asocia el nombre {namespace}local del elemento raiz de cada complemento con su manejador.
 type ComplementRegistry map[string]ComplementFactory
 
+// DefaultCFDI40Complements returns the handlers of every complement supported by this library.
 func DefaultCFDI40Complements() ComplementRegistry {
 	return ComplementRegistry{
-		"{http://www.sat.gob.mx/TimbreFiscalDigital}TimbreFiscalDigital": func(config HandlerConfig) ComplementHandler {
+		complementKey(tfd11Element): func(config HandlerConfig) ComplementHandler {
 			return NewTFD11Handler(config)
 		},
+		complementKey(nomina12Element): func(config HandlerConfig) ComplementHandler {
+			return NewNomina12Handler(config)
+		},
+		complementKey(pagos20Element): func(config HandlerConfig) ComplementHandler {
+			return NewPagos20Handler(config)
+		},
+		complementKey(pagos10Element): func(config HandlerConfig) ComplementHandler {
+			return NewPagos10Handler(config)
+		},
+		complementKey(ventaVehiculos11Element): func(config HandlerConfig) ComplementHandler {
+			return NewVentaVehiculos11Handler(config)
+		},
+		complementKey(leyendasFiscales10Element): func(config HandlerConfig) ComplementHandler {
+			return NewLeyendasFiscales10Handler(config)
+		},
+		complementKey(donatarias11Element): func(config HandlerConfig) ComplementHandler {
+			return NewDonatarias11Handler(config)
+		},
+		complementKey(detallistaElement): func(config HandlerConfig) ComplementHandler {
+			return NewDetallistaHandler(config)
+		},
+		complementKey(iedu10Element): func(config HandlerConfig) ComplementHandler {
+			return NewIEDU10Handler(config)
+		},
+		complementKey(servicioParcial10Element): func(config HandlerConfig) ComplementHandler {
+			return NewServicioParcial10Handler(config)
+		},
+		complementKey(turistaPasajeroExtranjero10Element): func(config HandlerConfig) ComplementHandler {
+			return NewTuristaPasajeroExtranjero10Handler(config)
+		},
+		complementKey(valesDeDespensa10Element): func(config HandlerConfig) ComplementHandler {
+			return NewValesDeDespensa10Handler(config)
+		},
+		complementKey(ingresosHidrocarburos10Element): func(config HandlerConfig) ComplementHandler {
+			return NewIngresosHidrocarburos10Handler(config)
+		},
+		complementKey(gastosHidrocarburos10Element): func(config HandlerConfig) ComplementHandler {
+			return NewGastosHidrocarburos10Handler(config)
+		},
 	}
 }
 
+func complementKey(name xml.Name) string {
+	return "{" + name.Space + "}" + name.Local
+}
+
+// findElement advances decoder to the first StartElement named name.
+//
+// A fragment cut from a document may use a prefix declared on an ancestor that is not part
+// of the fragment, e.g. <pago20:Pagos> without xmlns:pago20. Its namespace cannot be
+// resolved, so an element whose namespace is empty or not declared in scope is matched by
+// its local name alone. An element in a declared namespace must match name exactly.
+func findElement(decoder *xml.Decoder, name xml.Name) (xml.StartElement, error) {
+	var scopes [][]string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, fmt.Errorf("%s element not found", name.Local)
+		}
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var uris []string
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					uris = append(uris, attr.Value)
+				}
+			}
+			scopes = append(scopes, uris)
+
+			if t.Name == name || (t.Name.Local == name.Local && !declaredNamespace(scopes, t.Name.Space)) {
+				return t, nil
+			}
+		case xml.EndElement:
+			scopes = scopes[:len(scopes)-1]
+		}
+	}
+}
+
+// declaredNamespace reports whether space is a namespace URI declared in scopes.
+// The decoder leaves the prefix as the namespace of an element whose prefix is not declared.
+func declaredNamespace(scopes [][]string, space string) bool {
+	if space == "" {
+		return false
+	}
+	for _, uris := range scopes {
+		for _, uri := range uris {
+			if uri == space {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// complementResult converts the typed result of a handler to the one of ComplementHandler,
+// so that an error is never returned together with a typed nil pointer.
+func complementResult[T any](data *T, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// AddendaParser convierte el XML de una addenda en datos estructurados.
+type AddendaParser interface {
+	ParseAddenda(raw []byte) (interface{}, error)
+}
+
+// AddendaParserFunc permite usar una funcion como AddendaParser.
+type AddendaParserFunc func(raw []byte) (interface{}, error)
+
+// ParseAddenda calls f(raw).
+func (f AddendaParserFunc) ParseAddenda(raw []byte) (interface{}, error) {
+	return f(raw)
+}
+
+// AddendaRegistry relaciona el nombre de una addenda, "{namespace}local" o solo "local", con su parser.
+type AddendaRegistry map[string]AddendaParser
+
+// DefaultAddendaParsers returns the addenda parsers registered by default.
+// Addendas without a registered parser are flattened with FlattenXML.
+func DefaultAddendaParsers() AddendaRegistry {
+	return AddendaRegistry{}
+}
+
 // getAttrValue gets the value of an attribute from a xml.StartElement.
 func getAttrValue(se xml.StartElement, name string) string {
 	for _, attr := range se.Attr {
@@ -0,0 +1,81 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// IEDU10Handler handles parsing of Instituciones Educativas Privadas 1.0 concept complement.
+type IEDU10Handler struct {
+	config HandlerConfig
+}
+
+// iedu10Element es el nombre del elemento raiz del complemento IEDU 1.0.
+var iedu10Element = xml.Name{Space: "http://www.sat.gob.mx/iedu", Local: "instEducativas"}
+
+// NewIEDU10Handler creates a new IEDU10Handler.
+func NewIEDU10Handler(config HandlerConfig) *IEDU10Handler {
+	return &IEDU10Handler{config: config}
+}
+
+// ProcessInstEducativasElement processes the instEducativas element from an existing decoder stream.
+func (h *IEDU10Handler) ProcessInstEducativasElement(se xml.StartElement, decoder *xml.Decoder) (*models.IEDU10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Instituciones Educativas, this handler only supports iedu version 1.0")
+	}
+	data := &models.IEDU10Data{
+		Version:        version,
+		NombreAlumno:   helpers.CompactString(h.config.EscDelimiters, getAttrValue(se, "nombreAlumno")),
+		CURP:           strings.ToUpper(getAttrValue(se, "CURP")),
+		NivelEducativo: getAttrValue(se, "nivelEducativo"),
+		AutRVOE:        getAttrValue(se, "autRVOE"),
+		RfcPago:        getAttrValueOrDefault(se, "rfcPago", h.config.EmptyChar),
+	}
+
+	// instEducativas has no children, skip until its end element
+	if err := decoder.Skip(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// TransformFromBytes parses a IEDU 1.0 XML byte slice.
+func (h *IEDU10Handler) TransformFromBytes(xmlBytes []byte) (*models.IEDU10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a IEDU 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *IEDU10Handler) TransformFromString(xmlString string) (*models.IEDU10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *IEDU10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *IEDU10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessInstEducativasElement.
+func (h *IEDU10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessInstEducativasElement(se, decoder))
+}
+
+// TransformFromReader parses a IEDU 1.0 XML document read from r.
+func (h *IEDU10Handler) TransformFromReader(r io.Reader) (*models.IEDU10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, iedu10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessInstEducativasElement(se, decoder)
+}
@@ -0,0 +1,101 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// IngresosHidrocarburos10Handler handles parsing of Ingresos Hidrocarburos 1.0 complement.
+type IngresosHidrocarburos10Handler struct {
+	config HandlerConfig
+}
+
+// ingresosHidrocarburos10Element es el nombre del elemento raiz del complemento Ingresos Hidrocarburos 1.0.
+var ingresosHidrocarburos10Element = xml.Name{Space: "http://www.sat.gob.mx/IngresosHidrocarburos10", Local: "IngresosHidrocarburos"}
+
+// NewIngresosHidrocarburos10Handler creates a new IngresosHidrocarburos10Handler.
+func NewIngresosHidrocarburos10Handler(config HandlerConfig) *IngresosHidrocarburos10Handler {
+	return &IngresosHidrocarburos10Handler{config: config}
+}
+
+// ProcessIngresosHidrocarburosElement processes the IngresosHidrocarburos element from an existing decoder stream.
+func (h *IngresosHidrocarburos10Handler) ProcessIngresosHidrocarburosElement(se xml.StartElement, decoder *xml.Decoder) (*models.IngresosHidrocarburos10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "Version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Ingresos Hidrocarburos, this handler only supports version 1.0")
+	}
+	data := &models.IngresosHidrocarburos10Data{
+		Version:                        version,
+		NumeroContrato:                 getAttrValue(se, "NumeroContrato"),
+		ContraprestacionPagadaOperador: getAttrValue(se, "ContraprestacionPagadaOperador"),
+		Porcentaje:                     getAttrValue(se, "Porcentaje"),
+		DocumentosRelacionados:         []models.DocumentoRelacionadoIEEH{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "DocumentoRelacionado" {
+				data.DocumentosRelacionados = append(data.DocumentosRelacionados, models.DocumentoRelacionadoIEEH{
+					FolioFiscalVinculado:      strings.ToUpper(getAttrValue(t, "FolioFiscalVinculado")),
+					FechaFolioFiscalVinculado: getAttrValue(t, "FechaFolioFiscalVinculado"),
+					Mes:                       getAttrValue(t, "Mes"),
+				})
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+// TransformFromBytes parses a Ingresos Hidrocarburos 1.0 XML byte slice.
+func (h *IngresosHidrocarburos10Handler) TransformFromBytes(xmlBytes []byte) (*models.IngresosHidrocarburos10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Ingresos Hidrocarburos 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *IngresosHidrocarburos10Handler) TransformFromString(xmlString string) (*models.IngresosHidrocarburos10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *IngresosHidrocarburos10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *IngresosHidrocarburos10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessIngresosHidrocarburosElement.
+func (h *IngresosHidrocarburos10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessIngresosHidrocarburosElement(se, decoder))
+}
+
+// TransformFromReader parses a Ingresos Hidrocarburos 1.0 XML document read from r.
+func (h *IngresosHidrocarburos10Handler) TransformFromReader(r io.Reader) (*models.IngresosHidrocarburos10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, ingresosHidrocarburos10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessIngresosHidrocarburosElement(se, decoder)
+}
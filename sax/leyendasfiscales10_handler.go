@@ -0,0 +1,98 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// LeyendasFiscales10Handler handles parsing of Leyendas Fiscales 1.0 complement.
+type LeyendasFiscales10Handler struct {
+	config HandlerConfig
+}
+
+// leyendasFiscales10Element es el nombre del elemento raiz del complemento Leyendas Fiscales 1.0.
+var leyendasFiscales10Element = xml.Name{Space: "http://www.sat.gob.mx/leyendasFiscales", Local: "LeyendasFiscales"}
+
+// NewLeyendasFiscales10Handler creates a new LeyendasFiscales10Handler.
+func NewLeyendasFiscales10Handler(config HandlerConfig) *LeyendasFiscales10Handler {
+	return &LeyendasFiscales10Handler{config: config}
+}
+
+// ProcessLeyendasFiscalesElement processes the LeyendasFiscales element from an existing decoder stream.
+func (h *LeyendasFiscales10Handler) ProcessLeyendasFiscalesElement(se xml.StartElement, decoder *xml.Decoder) (*models.LeyendasFiscales10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Leyendas Fiscales, this handler only supports Leyendas Fiscales version 1.0")
+	}
+	data := &models.LeyendasFiscales10Data{
+		Version:  version,
+		Leyendas: []models.Leyenda{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "Leyenda" {
+				data.Leyendas = append(data.Leyendas, models.Leyenda{
+					DisposicionFiscal: getAttrValue(t, "disposicionFiscal"),
+					Norma:             getAttrValue(t, "norma"),
+					TextoLeyenda:      getAttrValue(t, "textoLeyenda"),
+				})
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+// TransformFromBytes parses a Leyendas Fiscales 1.0 XML byte slice.
+func (h *LeyendasFiscales10Handler) TransformFromBytes(xmlBytes []byte) (*models.LeyendasFiscales10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Leyendas Fiscales 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *LeyendasFiscales10Handler) TransformFromString(xmlString string) (*models.LeyendasFiscales10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *LeyendasFiscales10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *LeyendasFiscales10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessLeyendasFiscalesElement.
+func (h *LeyendasFiscales10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessLeyendasFiscalesElement(se, decoder))
+}
+
+// TransformFromReader parses a Leyendas Fiscales 1.0 XML document read from r.
+func (h *LeyendasFiscales10Handler) TransformFromReader(r io.Reader) (*models.LeyendasFiscales10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, leyendasFiscales10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessLeyendasFiscalesElement(se, decoder)
+}
@@ -14,6 +14,9 @@ type Nomina12Handler struct {
 	config HandlerConfig
 }
 
+// nomina12Element es el nombre del elemento raiz del complemento Nomina 1.2.
+var nomina12Element = xml.Name{Space: "http://www.sat.gob.mx/nomina12", Local: "Nomina"}
+
 func NewNomina12Handler(config HandlerConfig) *Nomina12Handler {
 	return &Nomina12Handler{config: config}
 }
@@ -41,6 +44,7 @@ func (h *Nomina12Handler) ProcessNomina12Element(se xml.StartElement, decoder *x
 		Incapacidades:     models.Nomina12Incapacidades{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -52,6 +56,10 @@ func (h *Nomina12Handler) ProcessNomina12Element(se xml.StartElement, decoder *x
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "Emisor":
 				data.Emisor = h.transformNomina12EmisorElement(t, decoder)
@@ -65,9 +73,11 @@ func (h *Nomina12Handler) ProcessNomina12Element(se xml.StartElement, decoder *x
 				data.OtrosPagos = h.transformNomina12OtrosPagosElement(t, decoder)
 			case "Incapacidades":
 				data.Incapacidades = h.transformNomina12IncapacidadesElement(t, decoder)
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Nomina12" || t.Name.Local == "Nomina" {
+			if scope.closes() {
 				return data, nil
 			}
 		}
@@ -75,54 +85,39 @@ func (h *Nomina12Handler) ProcessNomina12Element(se xml.StartElement, decoder *x
 	return data, nil
 }
 
-func (h *Nomina12Handler) transformBytes(xmlBytes []byte) (*models.Nomina12Data, error) {
-	return h.transformString(string(xmlBytes))
+// TransformFromBytes parses a Nomina 1.2 XML byte slice.
+func (h *Nomina12Handler) TransformFromBytes(xmlBytes []byte) (*models.Nomina12Data, error) {
+	return h.TransformFromString(string(xmlBytes))
 }
 
-func (h *Nomina12Handler) transformString(xmlString string) (*models.Nomina12Data, error) {
-	data := &models.Nomina12Data{
-		Emisor:        models.Nomina12Emisor{},
-		Receptor:      models.Nomina12Receptor{},
-		Percepciones:  models.Nomina12Percepciones{},
-		Deducciones:   models.Nomina12Deducciones{},
-		OtrosPagos:    models.Nomina12OtrosPagos{},
-		Incapacidades: models.Nomina12Incapacidades{},
-	}
+// TransformFromString parses a Nomina 1.2 XML string, either the complement alone or a whole document containing it.
+func (h *Nomina12Handler) TransformFromString(xmlString string) (*models.Nomina12Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
 
-	decoder := xml.NewDecoder(strings.NewReader(xmlString))
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *Nomina12Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
 
-	for {
-		token, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *Nomina12Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
 
-		switch t := token.(type) {
-		case xml.StartElement:
-			switch t.Name.Local {
-			case "Emisor":
-				data.Emisor = h.transformNomina12EmisorElement(t, decoder)
-			case "Receptor":
-				data.Receptor = h.transformNomina12ReceptorElement(t, decoder)
-			case "Percepciones":
-				data.Percepciones = h.transformNomina12PercepcionesElement(t, decoder)
-			case "Deducciones":
-				data.Deducciones = h.transformNomina12DeduccionesElement(t, decoder)
-			case "OtrosPagos":
-				data.OtrosPagos = h.transformNomina12OtrosPagosElement(t, decoder)
-			case "Incapacidades":
-				data.Incapacidades = h.transformNomina12IncapacidadesElement(t, decoder)
-			}
-		case xml.EndElement:
-			if t.Name.Local == "Nomina12" || t.Name.Local == "Nomina" {
-				return data, nil
-			}
-		}
+// ProcessElement implements ComplementHandler with ProcessNomina12Element.
+func (h *Nomina12Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessNomina12Element(se, decoder))
+}
+
+// TransformFromReader parses a Nomina 1.2 XML document read from r.
+func (h *Nomina12Handler) TransformFromReader(r io.Reader) (*models.Nomina12Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, nomina12Element)
+	if err != nil {
+		return nil, err
 	}
-	return data, nil
+	return h.ProcessNomina12Element(se, decoder)
 }
 
 func (h *Nomina12Handler) transformNomina12EmisorElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Emisor {
@@ -133,6 +128,7 @@ func (h *Nomina12Handler) transformNomina12EmisorElement(se xml.StartElement, de
 		EntidadSNCF:      models.EntidadSNCF{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -143,12 +139,18 @@ func (h *Nomina12Handler) transformNomina12EmisorElement(se xml.StartElement, de
 		}
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "EntidadSNCF":
 				emisor.EntidadSNCF = h.transformEntidadSNCFElement(t, decoder)
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Emisor" {
+			if scope.closes() {
 				return emisor
 			}
 		}
@@ -157,10 +159,12 @@ func (h *Nomina12Handler) transformNomina12EmisorElement(se xml.StartElement, de
 }
 
 func (h *Nomina12Handler) transformEntidadSNCFElement(se xml.StartElement, decoder *xml.Decoder) models.EntidadSNCF {
-	return models.EntidadSNCF{
+	entidadSNCF := models.EntidadSNCF{
 		OrigenRecurso:      getAttrValue(se, "OrigenRecurso"),
 		MontoRecursoPropio: helpers.GetOrDefault(getAttrValue(se, "MontoRecursoPropio"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return entidadSNCF
 }
 
 func (h *Nomina12Handler) transformNomina12ReceptorElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Receptor {
@@ -186,6 +190,7 @@ func (h *Nomina12Handler) transformNomina12ReceptorElement(se xml.StartElement,
 		Subcontrataciones:      []models.Subcontratacion{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -197,12 +202,18 @@ func (h *Nomina12Handler) transformNomina12ReceptorElement(se xml.StartElement,
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "SubContratacion":
 				receptor.Subcontrataciones = append(receptor.Subcontrataciones, h.transformSubcontratacionElement(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Receptor" {
+			if scope.closes() {
 				return receptor
 			}
 		}
@@ -211,10 +222,12 @@ func (h *Nomina12Handler) transformNomina12ReceptorElement(se xml.StartElement,
 }
 
 func (h *Nomina12Handler) transformSubcontratacionElement(se xml.StartElement, decoder *xml.Decoder) models.Subcontratacion {
-	return models.Subcontratacion{
+	subcontratacion := models.Subcontratacion{
 		RfcLabora:        getAttrValue(se, "RfcLabora"),
 		PorcentajeTiempo: helpers.GetOrDefault(getAttrValue(se, "PorcentajeTiempo"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return subcontratacion
 }
 
 func (h *Nomina12Handler) transformNomina12PercepcionesElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Percepciones {
@@ -229,6 +242,7 @@ func (h *Nomina12Handler) transformNomina12PercepcionesElement(se xml.StartEleme
 		SeparacionIndemnizacion:      models.SeparacionIndemnizacion{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -240,6 +254,10 @@ func (h *Nomina12Handler) transformNomina12PercepcionesElement(se xml.StartEleme
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "Percepcion":
 				Percepciones.Percepcion = append(Percepciones.Percepcion, h.transformNomina12PercepcionElement(t, decoder))
@@ -247,9 +265,11 @@ func (h *Nomina12Handler) transformNomina12PercepcionesElement(se xml.StartEleme
 				Percepciones.JubilacionPensionRetiro = h.transformJubilacionPensionRetiroElement(t, decoder)
 			case "SeparacionIndemnizacion":
 				Percepciones.SeparacionIndemnizacion = h.transformSeparacionIndemnizacionElement(t, decoder)
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Percepciones" {
+			if scope.closes() {
 				return Percepciones
 			}
 		}
@@ -268,6 +288,7 @@ func (h *Nomina12Handler) transformNomina12PercepcionElement(se xml.StartElement
 		HorasExtra:       []models.HorasExtra{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -278,14 +299,20 @@ func (h *Nomina12Handler) transformNomina12PercepcionElement(se xml.StartElement
 		}
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "AccionesOTitulos":
 				percepcion.AccionesOTitulos = h.transformAccionesOTitulosElement(t, decoder)
 			case "HorasExtra":
 				percepcion.HorasExtra = append(percepcion.HorasExtra, h.transformHorasExtraElement(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Percepcion" {
+			if scope.closes() {
 				return percepcion
 			}
 		}
@@ -294,39 +321,47 @@ func (h *Nomina12Handler) transformNomina12PercepcionElement(se xml.StartElement
 }
 
 func (h *Nomina12Handler) transformAccionesOTitulosElement(se xml.StartElement, decoder *xml.Decoder) models.AccionesOTitulos {
-	return models.AccionesOTitulos{
+	accionesOTitulos := models.AccionesOTitulos{
 		ValorMercado:      helpers.GetOrDefault(getAttrValue(se, "ValorMercado"), h.config.EmptyChar, h.config.SafeNumerics),
 		PrecioAlOtorgarse: helpers.GetOrDefault(getAttrValue(se, "PrecioAlOtorgarse"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return accionesOTitulos
 }
 
 func (h *Nomina12Handler) transformHorasExtraElement(se xml.StartElement, decoder *xml.Decoder) models.HorasExtra {
-	return models.HorasExtra{
+	horasExtra := models.HorasExtra{
 		Dias:          helpers.GetOrDefault(getAttrValue(se, "Dias"), h.config.EmptyChar, h.config.SafeNumerics),
 		TipoHoras:     getAttrValue(se, "TipoHoras"),
 		HorasExtra:    helpers.GetOrDefault(getAttrValue(se, "HorasExtra"), h.config.EmptyChar, h.config.SafeNumerics),
 		ImportePagado: helpers.GetOrDefault(getAttrValue(se, "ImportePagado"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return horasExtra
 }
 
 func (h *Nomina12Handler) transformJubilacionPensionRetiroElement(se xml.StartElement, decoder *xml.Decoder) models.JubilacionPensionRetiro {
-	return models.JubilacionPensionRetiro{
+	jubilacionPensionRetiro := models.JubilacionPensionRetiro{
 		TotalUnaExhibicion:  helpers.GetOrDefault(getAttrValue(se, "TotalUnaExhibicion"), h.config.EmptyChar, h.config.SafeNumerics),
 		TotalParcialidad:    helpers.GetOrDefault(getAttrValue(se, "TotalParcialidad"), h.config.EmptyChar, h.config.SafeNumerics),
 		MontoDiario:         helpers.GetOrDefault(getAttrValue(se, "MontoDiario"), h.config.EmptyChar, h.config.SafeNumerics),
 		IngresoAcumulable:   helpers.GetOrDefault(getAttrValue(se, "IngresoAcumulable"), h.config.EmptyChar, h.config.SafeNumerics),
 		IngresoNoAcumulable: helpers.GetOrDefault(getAttrValue(se, "IngresoNoAcumulable"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return jubilacionPensionRetiro
 }
 
 func (h *Nomina12Handler) transformSeparacionIndemnizacionElement(se xml.StartElement, decoder *xml.Decoder) models.SeparacionIndemnizacion {
-	return models.SeparacionIndemnizacion{
+	separacionIndemnizacion := models.SeparacionIndemnizacion{
 		TotalPagado:         helpers.GetOrDefault(getAttrValue(se, "TotalPagado"), h.config.EmptyChar, h.config.SafeNumerics),
 		NumAnosServicio:     helpers.GetOrDefault(getAttrValue(se, "NumAñosServicio"), h.config.EmptyChar, h.config.SafeNumerics),
 		UltimoSueldoMensOrd: helpers.GetOrDefault(getAttrValue(se, "UltimoSueldoMensOrd"), h.config.EmptyChar, h.config.SafeNumerics),
 		IngresoAcumulable:   helpers.GetOrDefault(getAttrValue(se, "IngresoAcumulable"), h.config.EmptyChar, h.config.SafeNumerics),
 		IngresoNoAcumulable: helpers.GetOrDefault(getAttrValue(se, "IngresoNoAcumulable"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return separacionIndemnizacion
 }
 
 func (h *Nomina12Handler) transformNomina12DeduccionesElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Deducciones {
@@ -336,6 +371,7 @@ func (h *Nomina12Handler) transformNomina12DeduccionesElement(se xml.StartElemen
 		Deduccion:               []models.Nomina12Deduccion{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -347,12 +383,18 @@ func (h *Nomina12Handler) transformNomina12DeduccionesElement(se xml.StartElemen
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "Deduccion":
 				deducciones.Deduccion = append(deducciones.Deduccion, h.transformNomina12DeduccionElement(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Deducciones" {
+			if scope.closes() {
 				return deducciones
 			}
 		}
@@ -361,12 +403,14 @@ func (h *Nomina12Handler) transformNomina12DeduccionesElement(se xml.StartElemen
 }
 
 func (h *Nomina12Handler) transformNomina12DeduccionElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Deduccion {
-	return models.Nomina12Deduccion{
+	nomina12Deduccion := models.Nomina12Deduccion{
 		TipoDeduccion: getAttrValue(se, "TipoDeduccion"),
 		Clave:         getAttrValue(se, "Clave"),
 		Concepto:      helpers.CompactString(h.config.EscDelimiters, getAttrValue(se, "Concepto")),
 		Importe:       helpers.GetOrDefault(getAttrValue(se, "Importe"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return nomina12Deduccion
 }
 
 func (h *Nomina12Handler) transformNomina12OtrosPagosElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12OtrosPagos {
@@ -374,6 +418,7 @@ func (h *Nomina12Handler) transformNomina12OtrosPagosElement(se xml.StartElement
 		OtroPago: []models.Nomina12OtroPago{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -385,12 +430,18 @@ func (h *Nomina12Handler) transformNomina12OtrosPagosElement(se xml.StartElement
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "OtroPago":
 				otrosPagos.OtroPago = append(otrosPagos.OtroPago, h.transformNomina12OtroPagoElement(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "OtrosPagos" {
+			if scope.closes() {
 				return otrosPagos
 			}
 		}
@@ -408,6 +459,7 @@ func (h *Nomina12Handler) transformNomina12OtroPagoElement(se xml.StartElement,
 		CompensacionSaldosAFavor: models.CompensacionSaldosAFavor{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -418,14 +470,20 @@ func (h *Nomina12Handler) transformNomina12OtroPagoElement(se xml.StartElement,
 		}
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "SubsidioAlEmpleo":
 				otroPago.SubsidioAlEmpleo = h.transformSubsidioAlEmpleoElement(t, decoder)
 			case "CompensacionSaldosAFavor":
 				otroPago.CompensacionSaldosAFavor = h.transformCompensacionSaldosAFavorElement(t, decoder)
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "OtroPago" {
+			if scope.closes() {
 				return otroPago
 			}
 		}
@@ -434,17 +492,21 @@ func (h *Nomina12Handler) transformNomina12OtroPagoElement(se xml.StartElement,
 }
 
 func (h *Nomina12Handler) transformSubsidioAlEmpleoElement(se xml.StartElement, decoder *xml.Decoder) models.SubsidioAlEmpleo {
-	return models.SubsidioAlEmpleo{
+	subsidioAlEmpleo := models.SubsidioAlEmpleo{
 		SubsidioCausado: helpers.GetOrDefault(getAttrValue(se, "SubsidioCausado"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return subsidioAlEmpleo
 }
 
 func (h *Nomina12Handler) transformCompensacionSaldosAFavorElement(se xml.StartElement, decoder *xml.Decoder) models.CompensacionSaldosAFavor {
-	return models.CompensacionSaldosAFavor{
+	compensacionSaldosAFavor := models.CompensacionSaldosAFavor{
 		SaldoAFavor:     helpers.GetOrDefault(getAttrValue(se, "SaldoAFavor"), h.config.EmptyChar, h.config.SafeNumerics),
 		Ano:             getAttrValue(se, "Año"),
 		RemanenteSalFav: helpers.GetOrDefault(getAttrValue(se, "RemanenteSalFav"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return compensacionSaldosAFavor
 }
 
 func (h *Nomina12Handler) transformNomina12IncapacidadesElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Incapacidades {
@@ -452,6 +514,7 @@ func (h *Nomina12Handler) transformNomina12IncapacidadesElement(se xml.StartElem
 		Incapacidad: []models.Nomina12Incapacidad{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -463,12 +526,18 @@ func (h *Nomina12Handler) transformNomina12IncapacidadesElement(se xml.StartElem
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "Incapacidad":
 				incapacidades.Incapacidad = append(incapacidades.Incapacidad, h.transformNomina12IncapacidadElement(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Incapacidades" {
+			if scope.closes() {
 				return incapacidades
 			}
 		}
@@ -477,9 +546,11 @@ func (h *Nomina12Handler) transformNomina12IncapacidadesElement(se xml.StartElem
 }
 
 func (h *Nomina12Handler) transformNomina12IncapacidadElement(se xml.StartElement, decoder *xml.Decoder) models.Nomina12Incapacidad {
-	return models.Nomina12Incapacidad{
+	nomina12Incapacidad := models.Nomina12Incapacidad{
 		DiasIncapacidad:  helpers.GetOrDefault(getAttrValue(se, "DiasIncapacidad"), h.config.EmptyChar, h.config.SafeNumerics),
 		TipoIncapacidad:  getAttrValue(se, "TipoIncapacidad"),
 		ImporteMonetario: helpers.GetOrDefault(getAttrValue(se, "ImporteMonetario"), h.config.EmptyChar, h.config.SafeNumerics),
 	}
+	skipElement(decoder)
+	return nomina12Incapacidad
 }
@@ -0,0 +1,346 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/money"
+)
+
+// Pagos10Handler handles parsing of Pagos 1.0 complement, used by CFDI 3.3 payment receipts.
+type Pagos10Handler struct {
+	config HandlerConfig
+}
+
+// pagos10Element es el nombre del elemento raiz del complemento Pagos 1.0.
+var pagos10Element = xml.Name{Space: "http://www.sat.gob.mx/Pagos", Local: "Pagos"}
+
+// NewPagos10Handler creates a new Pagos10Handler.
+func NewPagos10Handler(cfg HandlerConfig) *Pagos10Handler {
+	return &Pagos10Handler{config: cfg}
+}
+
+// ProcessPagosElement processes the Pagos element from an existing decoder stream.
+func (h *Pagos10Handler) ProcessPagosElement(se xml.StartElement, decoder *xml.Decoder) (*models.Pagos10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "Version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Pagos, this handler only supports Pagos version 1.0")
+	}
+
+	data := &models.Pagos10Data{
+		Version: version,
+		Pagos:   []models.Pago10{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			if t.Name.Local == "Pago" {
+				data.Pagos = append(data.Pagos, h.transformPago(t, decoder))
+			} else {
+				scope.enter()
+			}
+
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+// TransformFromBytes parses a Pagos 1.0 XML byte slice.
+func (h *Pagos10Handler) TransformFromBytes(xmlBytes []byte) (*models.Pagos10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Pagos 1.0 XML string, either the complement alone or a whole CFDI 3.3 document.
+func (h *Pagos10Handler) TransformFromString(xmlString string) (*models.Pagos10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *Pagos10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *Pagos10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessPagosElement.
+func (h *Pagos10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessPagosElement(se, decoder))
+}
+
+// TransformFromReader parses a Pagos 1.0 XML document read from r.
+func (h *Pagos10Handler) TransformFromReader(r io.Reader) (*models.Pagos10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, pagos10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessPagosElement(se, decoder)
+}
+
+// ToPagos20 normalizes Pagos 1.0 data into the Pagos 2.0 shape.
+// TipoCambioDR maps to EquivalenciaDR, the Impuestos of each Pago map to ImpuestosP and
+// Totales are computed in MXN from the Monto and Impuestos of each Pago: every amount is
+// converted with its TipoCambioP and the sums are rounded once with money.Round, half away from zero.
+// Bases, ObjetoImpDR and ImpuestosDR do not exist in Pagos 1.0, so BaseP, ObjetoImpDR and the
+// TotalTrasladosBase* totals are left as EmptyChar, even with SafeNumerics, instead of reporting a zero base.
+func (h *Pagos10Handler) ToPagos20(data *models.Pagos10Data) *models.Pagos20Data {
+	result := &models.Pagos20Data{
+		Version: data.Version,
+		Pagos:   make([]models.Pago20, 0, len(data.Pagos)),
+	}
+
+	totals := map[string]decimal.Decimal{}
+	add := func(key string, val, tipoCambio decimal.Decimal) {
+		totals[key] = totals[key].Add(val.Mul(tipoCambio))
+	}
+
+	for _, pago := range data.Pagos {
+		pago20 := models.Pago20{
+			FechaPago:        pago.FechaPago,
+			FormaDePagoP:     pago.FormaDePagoP,
+			MonedaP:          pago.MonedaP,
+			TipoCambioP:      pago.TipoCambioP,
+			Monto:            pago.Monto,
+			NumOperacion:     pago.NumOperacion,
+			RfcEmisorCtaOrd:  pago.RfcEmisorCtaOrd,
+			NomBancoOrdExt:   pago.NomBancoOrdExt,
+			CtaOrdenante:     pago.CtaOrdenante,
+			RfcEmisorCtaBen:  pago.RfcEmisorCtaBen,
+			CtaBeneficiario:  pago.CtaBeneficiario,
+			TipoCadPago:      pago.TipoCadPago,
+			CertPago:         pago.CertPago,
+			CadPago:          pago.CadPago,
+			SelloPago:        pago.SelloPago,
+			DoctoRelacionado: make([]models.DoctoRelacionado20, 0, len(pago.DoctoRelacionado)),
+			ImpuestosP:       make([]models.ImpuestosP, 0, len(pago.Impuestos)),
+		}
+
+		tipoCambio := helpers.TryParseDecimal(pago.TipoCambioP)
+		if tipoCambio.IsZero() {
+			tipoCambio = decimal.NewFromInt(1)
+		}
+		add("MontoTotalPagos", helpers.TryParseDecimal(pago.Monto), tipoCambio)
+
+		for _, docto := range pago.DoctoRelacionado {
+			pago20.DoctoRelacionado = append(pago20.DoctoRelacionado, models.DoctoRelacionado20{
+				IdDocumento:      docto.IdDocumento,
+				Serie:            docto.Serie,
+				Folio:            docto.Folio,
+				MonedaDR:         docto.MonedaDR,
+				EquivalenciaDR:   docto.TipoCambioDR,
+				NumParcialidad:   docto.NumParcialidad,
+				ImpSaldoAnt:      docto.ImpSaldoAnt,
+				ImpPagado:        docto.ImpPagado,
+				ImpSaldoInsoluto: docto.ImpSaldoInsoluto,
+				ObjetoImpDR:      h.config.EmptyChar,
+				ImpuestosDR:      []models.ImpuestosDR{},
+			})
+		}
+
+		for _, impuestos := range pago.Impuestos {
+			impuestosP := models.ImpuestosP{
+				RetencionesP: make([]models.RetencionP, 0, len(impuestos.Retenciones)),
+				TrasladosP:   make([]models.TrasladoP, 0, len(impuestos.Traslados)),
+			}
+			for _, retencion := range impuestos.Retenciones {
+				impuestosP.RetencionesP = append(impuestosP.RetencionesP, models.RetencionP{
+					ImpuestoP: retencion.Impuesto,
+					ImporteP:  retencion.Importe,
+				})
+				switch retencion.Impuesto {
+				case "001":
+					add("TotalRetencionesISR", helpers.TryParseDecimal(retencion.Importe), tipoCambio)
+				case "002":
+					add("TotalRetencionesIVA", helpers.TryParseDecimal(retencion.Importe), tipoCambio)
+				case "003":
+					add("TotalRetencionesIEPS", helpers.TryParseDecimal(retencion.Importe), tipoCambio)
+				}
+			}
+			for _, traslado := range impuestos.Traslados {
+				impuestosP.TrasladosP = append(impuestosP.TrasladosP, models.TrasladoP{
+					BaseP:       h.config.EmptyChar,
+					ImpuestoP:   traslado.Impuesto,
+					TipoFactorP: traslado.TipoFactor,
+					TasaOCuotaP: traslado.TasaOCuota,
+					ImporteP:    traslado.Importe,
+				})
+				if traslado.Impuesto != "002" || traslado.TipoFactor != "Tasa" {
+					continue
+				}
+				switch helpers.TryParseDecimal(traslado.TasaOCuota).StringFixed(6) {
+				case "0.160000":
+					add("TotalTrasladosImpuestoIVA16", helpers.TryParseDecimal(traslado.Importe), tipoCambio)
+				case "0.080000":
+					add("TotalTrasladosImpuestoIVA8", helpers.TryParseDecimal(traslado.Importe), tipoCambio)
+				case "0.000000":
+					add("TotalTrasladosImpuestoIVA0", helpers.TryParseDecimal(traslado.Importe), tipoCambio)
+				}
+			}
+			pago20.ImpuestosP = append(pago20.ImpuestosP, impuestosP)
+		}
+		pago20.ResumenImpuestosP = resumenImpuestosP(pago20)
+
+		result.Pagos = append(result.Pagos, pago20)
+	}
+
+	total := func(key string) string {
+		val, ok := totals[key]
+		if !ok {
+			return helpers.GetOrDefault("", h.config.EmptyChar, h.config.SafeNumerics)
+		}
+		return money.Round(val, money.MXN).StringFixed(money.Decimals(money.MXN))
+	}
+	result.Totales = models.Totales20{
+		TotalRetencionesIVA:         total("TotalRetencionesIVA"),
+		TotalRetencionesISR:         total("TotalRetencionesISR"),
+		TotalRetencionesIEPS:        total("TotalRetencionesIEPS"),
+		TotalTrasladosBaseIVA16:     h.config.EmptyChar,
+		TotalTrasladosImpuestoIVA16: total("TotalTrasladosImpuestoIVA16"),
+		TotalTrasladosBaseIVA8:      h.config.EmptyChar,
+		TotalTrasladosImpuestoIVA8:  total("TotalTrasladosImpuestoIVA8"),
+		TotalTrasladosBaseIVA0:      h.config.EmptyChar,
+		TotalTrasladosImpuestoIVA0:  total("TotalTrasladosImpuestoIVA0"),
+		TotalTrasladosBaseIVAExento: h.config.EmptyChar,
+		MontoTotalPagos:             total("MontoTotalPagos"),
+	}
+	result.ResumenImpuestos = resumenImpuestosPagos(result.Pagos)
+
+	return result
+}
+
+func (h *Pagos10Handler) transformPago(se xml.StartElement, decoder *xml.Decoder) models.Pago10 {
+	pago := models.Pago10{
+		FechaPago:        getAttrValue(se, "FechaPago"),
+		FormaDePagoP:     getAttrValue(se, "FormaDePagoP"),
+		MonedaP:          getAttrValue(se, "MonedaP"),
+		TipoCambioP:      helpers.GetOrDefaultOne(getAttrValue(se, "TipoCambioP"), h.config.EmptyChar, h.config.SafeNumerics),
+		Monto:            getAttrValue(se, "Monto"),
+		NumOperacion:     helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "NumOperacion", h.config.EmptyChar)),
+		RfcEmisorCtaOrd:  getAttrValueOrDefault(se, "RfcEmisorCtaOrd", h.config.EmptyChar),
+		NomBancoOrdExt:   helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "NomBancoOrdExt", h.config.EmptyChar)),
+		CtaOrdenante:     getAttrValueOrDefault(se, "CtaOrdenante", h.config.EmptyChar),
+		RfcEmisorCtaBen:  getAttrValueOrDefault(se, "RfcEmisorCtaBen", h.config.EmptyChar),
+		CtaBeneficiario:  getAttrValueOrDefault(se, "CtaBeneficiario", h.config.EmptyChar),
+		TipoCadPago:      getAttrValueOrDefault(se, "TipoCadPago", h.config.EmptyChar),
+		CertPago:         helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "CertPago", h.config.EmptyChar)),
+		CadPago:          helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "CadPago", h.config.EmptyChar)),
+		SelloPago:        helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "SelloPago", h.config.EmptyChar)),
+		DoctoRelacionado: []models.DoctoRelacionado10{},
+		Impuestos:        []models.ImpuestosPago10{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return pago
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			switch t.Name.Local {
+			case "DoctoRelacionado":
+				pago.DoctoRelacionado = append(pago.DoctoRelacionado, h.transformDoctoRelacionado(t))
+				scope.enter()
+
+			case "Impuestos":
+				pago.Impuestos = append(pago.Impuestos, h.transformImpuestos(t, decoder))
+
+			default:
+				scope.enter()
+			}
+
+		case xml.EndElement:
+			if scope.closes() {
+				return pago
+			}
+		}
+	}
+}
+
+func (h *Pagos10Handler) transformDoctoRelacionado(se xml.StartElement) models.DoctoRelacionado10 {
+	return models.DoctoRelacionado10{
+		IdDocumento:      getAttrValue(se, "IdDocumento"),
+		Serie:            helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "Serie", h.config.EmptyChar)),
+		Folio:            helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "Folio", h.config.EmptyChar)),
+		MonedaDR:         getAttrValue(se, "MonedaDR"),
+		TipoCambioDR:     helpers.GetOrDefaultOne(getAttrValue(se, "TipoCambioDR"), h.config.EmptyChar, h.config.SafeNumerics),
+		MetodoDePagoDR:   getAttrValue(se, "MetodoDePagoDR"),
+		NumParcialidad:   getAttrValueOrDefault(se, "NumParcialidad", h.config.EmptyChar),
+		ImpSaldoAnt:      helpers.GetOrDefault(getAttrValue(se, "ImpSaldoAnt"), h.config.EmptyChar, h.config.SafeNumerics),
+		ImpPagado:        helpers.GetOrDefault(getAttrValue(se, "ImpPagado"), h.config.EmptyChar, h.config.SafeNumerics),
+		ImpSaldoInsoluto: helpers.GetOrDefault(getAttrValue(se, "ImpSaldoInsoluto"), h.config.EmptyChar, h.config.SafeNumerics),
+	}
+}
+
+func (h *Pagos10Handler) transformImpuestos(se xml.StartElement, decoder *xml.Decoder) models.ImpuestosPago10 {
+	impuestos := models.ImpuestosPago10{
+		TotalImpuestosRetenidos:   helpers.GetOrDefault(getAttrValue(se, "TotalImpuestosRetenidos"), h.config.EmptyChar, h.config.SafeNumerics),
+		TotalImpuestosTrasladados: helpers.GetOrDefault(getAttrValue(se, "TotalImpuestosTrasladados"), h.config.EmptyChar, h.config.SafeNumerics),
+		Retenciones:               []models.Retencion10{},
+		Traslados:                 []models.Traslado10{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return impuestos
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			switch t.Name.Local {
+			case "Retencion":
+				impuestos.Retenciones = append(impuestos.Retenciones, models.Retencion10{
+					Impuesto: getAttrValue(t, "Impuesto"),
+					Importe:  getAttrValue(t, "Importe"),
+				})
+
+			case "Traslado":
+				impuestos.Traslados = append(impuestos.Traslados, models.Traslado10{
+					Impuesto:   getAttrValue(t, "Impuesto"),
+					TipoFactor: getAttrValue(t, "TipoFactor"),
+					TasaOCuota: getAttrValue(t, "TasaOCuota"),
+					Importe:    getAttrValue(t, "Importe"),
+				})
+			}
+
+		case xml.EndElement:
+			if scope.closes() {
+				return impuestos
+			}
+		}
+	}
+}
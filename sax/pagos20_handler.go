@@ -15,6 +15,9 @@ type Pagos20Handler struct {
 	config HandlerConfig
 }
 
+// pagos20Element es el nombre del elemento raiz del complemento Pagos 2.0.
+var pagos20Element = xml.Name{Space: "http://www.sat.gob.mx/Pagos20", Local: "Pagos"}
+
 // NewPagos20Handler creates a new Pagos20Handler.
 func NewPagos20Handler(cfg HandlerConfig) *Pagos20Handler {
 	return &Pagos20Handler{config: cfg}
@@ -32,6 +35,7 @@ func (h *Pagos20Handler) ProcessPagosElement(se xml.StartElement, decoder *xml.D
 		Pagos:   []models.Pago20{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -40,17 +44,26 @@ func (h *Pagos20Handler) ProcessPagosElement(se xml.StartElement, decoder *xml.D
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "Totales":
 				h.transformTotales(t, data)
+				scope.enter()
 
 			case "Pago":
 				pago := h.transformPago(t, decoder)
 				data.Pagos = append(data.Pagos, pago)
+
+			default:
+				scope.enter()
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "Pagos" {
+			if scope.closes() {
+				data.ResumenImpuestos = resumenImpuestosPagos(data.Pagos)
 				return data, nil
 			}
 		}
@@ -62,44 +75,34 @@ func (h *Pagos20Handler) TransformFromBytes(xmlBytes []byte) (*models.Pagos20Dat
 	return h.TransformFromString(string(xmlBytes))
 }
 
-// TransformFromString parses a Pagos 2.0 XML string.
-func (h *Pagos20Handler) TransformFromString(xmlStr string) (*models.Pagos20Data, error) {
-	data := &models.Pagos20Data{
-		Pagos: []models.Pago20{},
-	}
-
-	decoder := xml.NewDecoder(strings.NewReader(xmlStr))
+// TransformFromString parses a Pagos 2.0 XML string, either the complement alone or a whole document containing it.
+func (h *Pagos20Handler) TransformFromString(xmlString string) (*models.Pagos20Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
 
-	for {
-		token, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *Pagos20Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
 
-		switch se := token.(type) {
-		case xml.StartElement:
-			switch se.Name.Local {
-			case "Pagos":
-				version := strings.TrimSpace(getAttrValue(se, "Version"))
-				if version != "2.0" {
-					return nil, errors.New("incorrect type of Pagos, this handler only supports Pagos version 2.0")
-				}
-				data.Version = version
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *Pagos20Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
 
-			case "Totales":
-				h.transformTotales(se, data)
+// ProcessElement implements ComplementHandler with ProcessPagosElement.
+func (h *Pagos20Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessPagosElement(se, decoder))
+}
 
-			case "Pago":
-				pago := h.transformPago(se, decoder)
-				data.Pagos = append(data.Pagos, pago)
-			}
-		}
+// TransformFromReader parses a Pagos 2.0 XML document read from r.
+func (h *Pagos20Handler) TransformFromReader(r io.Reader) (*models.Pagos20Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, pagos20Element)
+	if err != nil {
+		return nil, err
 	}
-
-	return data, nil
+	return h.ProcessPagosElement(se, decoder)
 }
 
 func (h *Pagos20Handler) transformTotales(se xml.StartElement, data *models.Pagos20Data) {
@@ -139,6 +142,7 @@ func (h *Pagos20Handler) transformPago(se xml.StartElement, decoder *xml.Decoder
 		ImpuestosP:       []models.ImpuestosP{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -147,6 +151,10 @@ func (h *Pagos20Handler) transformPago(se xml.StartElement, decoder *xml.Decoder
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "DoctoRelacionado":
 				docto := h.transformDoctoRelacionado(t, decoder)
@@ -155,10 +163,14 @@ func (h *Pagos20Handler) transformPago(se xml.StartElement, decoder *xml.Decoder
 			case "ImpuestosP":
 				impuestos := h.transformImpuestosP(t, decoder)
 				pago.ImpuestosP = append(pago.ImpuestosP, impuestos)
+
+			default:
+				scope.enter()
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "Pago" {
+			if scope.closes() {
+				pago.ResumenImpuestosP = resumenImpuestosP(pago)
 				return pago
 			}
 		}
@@ -180,6 +192,7 @@ func (h *Pagos20Handler) transformDoctoRelacionado(se xml.StartElement, decoder
 		ImpuestosDR:      []models.ImpuestosDR{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -188,13 +201,19 @@ func (h *Pagos20Handler) transformDoctoRelacionado(se xml.StartElement, decoder
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			if t.Name.Local == "ImpuestosDR" {
 				impuestosDR := h.transformImpuestosDR(t, decoder)
 				docto.ImpuestosDR = append(docto.ImpuestosDR, impuestosDR)
+			} else {
+				scope.enter()
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "DoctoRelacionado" {
+			if scope.closes() {
 				return docto
 			}
 		}
@@ -207,6 +226,7 @@ func (h *Pagos20Handler) transformImpuestosDR(se xml.StartElement, decoder *xml.
 		TrasladosDR:   []models.ImpuestoDRItem{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -215,6 +235,11 @@ func (h *Pagos20Handler) transformImpuestosDR(se xml.StartElement, decoder *xml.
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
 			switch t.Name.Local {
 			case "RetencionDR":
 				item := models.ImpuestoDRItem{
@@ -238,7 +263,7 @@ func (h *Pagos20Handler) transformImpuestosDR(se xml.StartElement, decoder *xml.
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "ImpuestosDR" {
+			if scope.closes() {
 				return impuestos
 			}
 		}
@@ -251,6 +276,7 @@ func (h *Pagos20Handler) transformImpuestosP(se xml.StartElement, decoder *xml.D
 		TrasladosP:   []models.TrasladoP{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -259,6 +285,11 @@ func (h *Pagos20Handler) transformImpuestosP(se xml.StartElement, decoder *xml.D
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
 			switch t.Name.Local {
 			case "RetencionP":
 				retencion := models.RetencionP{
@@ -279,7 +310,7 @@ func (h *Pagos20Handler) transformImpuestosP(se xml.StartElement, decoder *xml.D
 			}
 
 		case xml.EndElement:
-			if t.Name.Local == "ImpuestosP" {
+			if scope.closes() {
 				return impuestos
 			}
 		}
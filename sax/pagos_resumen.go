@@ -0,0 +1,119 @@
+package sax
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/money"
+)
+
+// Tipos de impuesto de ResumenImpuestoP.
+const (
+	ResumenTraslado  = "traslado"
+	ResumenRetencion = "retencion"
+)
+
+// resumenImpuestos accumulates tax amounts by tipo, impuesto, tipo factor and tasa o cuota, keeping the order of first appearance.
+type resumenImpuestos struct {
+	keys    []models.ResumenImpuestoP
+	base    map[models.ResumenImpuestoP]decimal.Decimal
+	hasBase map[models.ResumenImpuestoP]bool
+	total   map[models.ResumenImpuestoP]decimal.Decimal
+}
+
+func newResumenImpuestos() *resumenImpuestos {
+	return &resumenImpuestos{
+		base:    map[models.ResumenImpuestoP]decimal.Decimal{},
+		hasBase: map[models.ResumenImpuestoP]bool{},
+		total:   map[models.ResumenImpuestoP]decimal.Decimal{},
+	}
+}
+
+// add accumulates importe under key, and base only when hasBase is true, i.e. when the
+// source had a base. Pagos 1.0 has no BaseP, so its keys keep an empty Base instead of zero.
+func (r *resumenImpuestos) add(key models.ResumenImpuestoP, base decimal.Decimal, hasBase bool, importe decimal.Decimal) {
+	if _, ok := r.total[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	if hasBase {
+		r.base[key] = r.base[key].Add(base)
+		r.hasBase[key] = true
+	}
+	r.total[key] = r.total[key].Add(importe)
+}
+
+func (r *resumenImpuestos) result() []models.ResumenImpuestoP {
+	if len(r.keys) == 0 {
+		return nil
+	}
+	result := make([]models.ResumenImpuestoP, 0, len(r.keys))
+	for _, key := range r.keys {
+		item := key
+		if key.Tipo == ResumenTraslado && r.hasBase[key] {
+			item.Base = formatMXN(r.base[key])
+		}
+		item.Importe = formatMXN(r.total[key])
+		result = append(result, item)
+	}
+	return result
+}
+
+// resumenImpuestosP summarizes the ImpuestosP of a Pago in MXN using its TipoCambioP.
+func resumenImpuestosP(pago models.Pago20) []models.ResumenImpuestoP {
+	tipoCambio := helpers.TryParseDecimal(pago.TipoCambioP)
+	if tipoCambio.IsZero() {
+		tipoCambio = decimal.NewFromInt(1)
+	}
+
+	resumen := newResumenImpuestos()
+	for _, impuestos := range pago.ImpuestosP {
+		for _, traslado := range impuestos.TrasladosP {
+			key := models.ResumenImpuestoP{
+				Tipo:       ResumenTraslado,
+				Impuesto:   traslado.ImpuestoP,
+				TipoFactor: traslado.TipoFactorP,
+				TasaOCuota: normalizeTasa(traslado.TasaOCuotaP),
+			}
+			base, err := decimal.NewFromString(traslado.BaseP)
+			resumen.add(key, base.Mul(tipoCambio), err == nil,
+				helpers.TryParseDecimal(traslado.ImporteP).Mul(tipoCambio))
+		}
+		for _, retencion := range impuestos.RetencionesP {
+			key := models.ResumenImpuestoP{
+				Tipo:     ResumenRetencion,
+				Impuesto: retencion.ImpuestoP,
+			}
+			resumen.add(key, decimal.Zero, false, helpers.TryParseDecimal(retencion.ImporteP).Mul(tipoCambio))
+		}
+	}
+	return resumen.result()
+}
+
+// resumenImpuestosPagos aggregates the ResumenImpuestosP of every Pago.
+func resumenImpuestosPagos(pagos []models.Pago20) []models.ResumenImpuestoP {
+	resumen := newResumenImpuestos()
+	for _, pago := range pagos {
+		for _, item := range pago.ResumenImpuestosP {
+			key := item
+			key.Base, key.Importe = "", ""
+			base, err := decimal.NewFromString(item.Base)
+			resumen.add(key, base, err == nil, helpers.TryParseDecimal(item.Importe))
+		}
+	}
+	return resumen.result()
+}
+
+// formatMXN rounds an MXN amount with money.Round, half away from zero as the SAT does, whatever
+// the MonedaP of the pago it was converted from.
+func formatMXN(amount decimal.Decimal) string {
+	return money.Round(amount, money.MXN).StringFixed(money.Decimals(money.MXN))
+}
+
+// normalizeTasa formats a tasa o cuota with six decimals so "0.16" and "0.160000" are grouped together.
+func normalizeTasa(tasa string) string {
+	d, err := decimal.NewFromString(tasa)
+	if err != nil {
+		return tasa
+	}
+	return d.StringFixed(6)
+}
@@ -0,0 +1,141 @@
+package sax
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// rawElement regresa el XML original de un elemento leido de xmlStr entre start y end.
+//
+// Un fragmento recortado del documento pierde los namespaces declarados en sus ancestros,
+// por ejemplo un complemento cuyo prefijo se declara en cfdi:Comprobante. Las declaraciones
+// que el fragmento usa sin declarar se vuelven a escribir en su elemento raiz para que se
+// pueda parsear por si solo.
+func rawElement(xmlStr string, start, end int64) []byte {
+	raw := xmlStr[start:end]
+	unbound := unboundPrefixes(raw)
+	if len(unbound) == 0 {
+		return []byte(raw)
+	}
+
+	scope := namespacesInScope(xmlStr[:start])
+	var decls strings.Builder
+	for _, prefix := range unbound {
+		uri, ok := scope[prefix]
+		if !ok {
+			continue
+		}
+		if prefix == "" {
+			decls.WriteString(` xmlns="`)
+		} else {
+			decls.WriteString(` xmlns:` + prefix + `="`)
+		}
+		xml.EscapeText(&decls, []byte(uri))
+		decls.WriteByte('"')
+	}
+	if decls.Len() == 0 {
+		return []byte(raw)
+	}
+
+	open := strings.IndexByte(raw, '<')
+	name := open + 1 + strings.IndexAny(raw[open+1:], " \t\r\n/>")
+	return []byte(raw[:name] + decls.String() + raw[name:])
+}
+
+// unboundPrefixes returns, in order of appearance, the prefixes used by the elements and
+// attributes of raw that are not declared inside raw. The empty prefix stands for the
+// default namespace of unprefixed elements.
+func unboundPrefixes(raw string) []string {
+	decoder := xml.NewDecoder(strings.NewReader(raw))
+	var stack [][]string
+	var unbound []string
+	seen := map[string]bool{}
+
+	declared := func(prefix string) bool {
+		for _, decls := range stack {
+			for _, d := range decls {
+				if d == prefix {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	use := func(prefix string) {
+		if prefix == "xml" || seen[prefix] || declared(prefix) {
+			return
+		}
+		seen[prefix] = true
+		unbound = append(unbound, prefix)
+	}
+
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return unbound
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, namespaceDecls(t))
+			use(t.Name.Space)
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "" && attr.Name.Space != "xmlns" {
+					use(attr.Name.Space)
+				}
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+// namespacesInScope returns the namespace URI bound to each prefix at the end of head,
+// a document cut right before an element.
+func namespacesInScope(head string) map[string]string {
+	decoder := xml.NewDecoder(strings.NewReader(head))
+	var stack []xml.StartElement
+
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Copy())
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	scope := map[string]string{}
+	for _, se := range stack {
+		for _, attr := range se.Attr {
+			switch {
+			case attr.Name.Space == "xmlns":
+				scope[attr.Name.Local] = attr.Value
+			case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+				scope[""] = attr.Value
+			}
+		}
+	}
+	return scope
+}
+
+// namespaceDecls returns the prefixes declared by the attributes of se.
+func namespaceDecls(se xml.StartElement) []string {
+	var prefixes []string
+	for _, attr := range se.Attr {
+		switch {
+		case attr.Name.Space == "xmlns":
+			prefixes = append(prefixes, attr.Name.Local)
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			prefixes = append(prefixes, "")
+		}
+	}
+	return prefixes
+}
@@ -0,0 +1,46 @@
+package sax
+
+import "encoding/xml"
+
+// elementScope sigue el anidamiento bajo un StartElement para que el ciclo que lo lee
+// termine en su propio EndElement, sin confundirlo con un elemento anidado del mismo
+// nombre local ni consumir los elementos hermanos que le siguen.
+//
+// Los ciclos de lectura siguen una regla: una funcion transform que recibe el decoder
+// consume su elemento hasta el EndElement; cualquier otro elemento propio leido por el
+// ciclo se registra con enter, y los elementos de otro namespace se saltan completos.
+type elementScope struct {
+	space string
+	depth int
+}
+
+func newElementScope(se xml.StartElement) *elementScope {
+	return &elementScope{space: se.Name.Space}
+}
+
+// owns reports whether t belongs to the namespace of the scope element.
+func (s *elementScope) owns(t xml.StartElement) bool {
+	return t.Name.Space == s.space
+}
+
+// enter records a StartElement whose EndElement will be read by the loop.
+func (s *elementScope) enter() {
+	s.depth++
+}
+
+// closes reports whether the EndElement just read closes the scope element.
+// The decoder rejects unbalanced documents, so at depth zero it can only be the scope's own.
+func (s *elementScope) closes() bool {
+	if s.depth == 0 {
+		return true
+	}
+	s.depth--
+	return false
+}
+
+// skipElement consumes the rest of an element whose StartElement was just read, including
+// its EndElement. A decoding error is returned again by the next decoder.Token call, so
+// transform functions that only read attributes can leave it to their caller.
+func skipElement(decoder *xml.Decoder) {
+	_ = decoder.Skip()
+}
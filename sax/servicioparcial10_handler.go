@@ -0,0 +1,109 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ServicioParcial10Handler handles parsing of Servicios Parciales de Construcción 1.0 complement.
+type ServicioParcial10Handler struct {
+	config HandlerConfig
+}
+
+// servicioParcial10Element es el nombre del elemento raiz del complemento Servicios Parciales de Construccion 1.0.
+var servicioParcial10Element = xml.Name{Space: "http://www.sat.gob.mx/servicioparcialconstruccion", Local: "parcialesconstruccion"}
+
+// NewServicioParcial10Handler creates a new ServicioParcial10Handler.
+func NewServicioParcial10Handler(config HandlerConfig) *ServicioParcial10Handler {
+	return &ServicioParcial10Handler{config: config}
+}
+
+// ProcessParcialesConstruccionElement processes the parcialesconstruccion element from an existing decoder stream.
+func (h *ServicioParcial10Handler) ProcessParcialesConstruccionElement(se xml.StartElement, decoder *xml.Decoder) (*models.ServicioParcial10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "Version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Servicios Parciales de Construccion, this handler only supports version 1.0")
+	}
+	data := &models.ServicioParcial10Data{
+		Version:       version,
+		NumPerLicoAut: getAttrValue(se, "NumPerLicoAut"),
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "Inmueble" {
+				data.Inmueble = h.transformInmueble(t)
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+func (h *ServicioParcial10Handler) transformInmueble(se xml.StartElement) models.Inmueble {
+	return models.Inmueble{
+		Calle:        helpers.CompactString(h.config.EscDelimiters, getAttrValue(se, "Calle")),
+		NoExterior:   getAttrValueOrDefault(se, "NoExterior", h.config.EmptyChar),
+		NoInterior:   getAttrValueOrDefault(se, "NoInterior", h.config.EmptyChar),
+		Colonia:      helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "Colonia", h.config.EmptyChar)),
+		Localidad:    helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "Localidad", h.config.EmptyChar)),
+		Referencia:   helpers.CompactString(h.config.EscDelimiters, getAttrValueOrDefault(se, "Referencia", h.config.EmptyChar)),
+		Municipio:    getAttrValue(se, "Municipio"),
+		Estado:       getAttrValue(se, "Estado"),
+		CodigoPostal: getAttrValue(se, "CodigoPostal"),
+	}
+}
+
+// TransformFromBytes parses a Servicios Parciales de Construccion 1.0 XML byte slice.
+func (h *ServicioParcial10Handler) TransformFromBytes(xmlBytes []byte) (*models.ServicioParcial10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Servicios Parciales de Construccion 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *ServicioParcial10Handler) TransformFromString(xmlString string) (*models.ServicioParcial10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *ServicioParcial10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *ServicioParcial10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessParcialesConstruccionElement.
+func (h *ServicioParcial10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessParcialesConstruccionElement(se, decoder))
+}
+
+// TransformFromReader parses a Servicios Parciales de Construccion 1.0 XML document read from r.
+func (h *ServicioParcial10Handler) TransformFromReader(r io.Reader) (*models.ServicioParcial10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, servicioParcial10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessParcialesConstruccionElement(se, decoder)
+}
@@ -20,33 +20,55 @@ func NewTFD11Handler(config HandlerConfig) *TFD11Handler {
 	return &TFD11Handler{config: config}
 }
 
+// tfd11Element es el nombre del elemento raiz del Timbre Fiscal Digital.
+var tfd11Element = xml.Name{Space: "http://www.sat.gob.mx/TimbreFiscalDigital", Local: "TimbreFiscalDigital"}
+
 // TransformFromBytes parses a TFD 1.1 XML byte slice.
-func (h *TFD11Handler) TransformFromBytes(xmlBytes []byte) (interface{}, error) {
+func (h *TFD11Handler) TransformFromBytes(xmlBytes []byte) (*models.TFD11, error) {
 	return h.TransformFromString(string(xmlBytes))
 }
 
-// TransformFromString parses a TFD 1.1 XML string.
+// TransformFromString parses a TFD 1.1 XML string, either the complement alone or a whole document containing it.
 func (h *TFD11Handler) TransformFromString(xmlStr string) (*models.TFD11, error) {
-	decoder := xml.NewDecoder(strings.NewReader(xmlStr))
-
-	for {
-		token, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+	return h.TransformFromReader(strings.NewReader(xmlStr))
+}
 
-		switch se := token.(type) {
-		case xml.StartElement:
-			if se.Name.Local == "TimbreFiscalDigital" {
-				return h.transformTFD(se)
-			}
-		}
+// ProcessTFDElement processes the TimbreFiscalDigital element from an existing decoder stream.
+func (h *TFD11Handler) ProcessTFDElement(se xml.StartElement, decoder *xml.Decoder) (*models.TFD11, error) {
+	tfd, err := h.transformTFD(se)
+	if err != nil {
+		return nil, err
 	}
+	// The TFD has no children, consume up to its end element
+	if err := decoder.Skip(); err != nil {
+		return nil, err
+	}
+	return tfd, nil
+}
+
+// ParseBytes implements ComplementHandler with TransformFromString.
+func (h *TFD11Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromString(string(xmlBytes)))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *TFD11Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
 
-	return nil, errors.New("TimbreFiscalDigital element not found")
+// ProcessElement implements ComplementHandler with ProcessTFDElement.
+func (h *TFD11Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessTFDElement(se, decoder))
+}
+
+// TransformFromReader parses a TFD 1.1 XML document read from r.
+func (h *TFD11Handler) TransformFromReader(r io.Reader) (*models.TFD11, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, tfd11Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessTFDElement(se, decoder)
 }
 
 func (h *TFD11Handler) transformTFD(se xml.StartElement) (*models.TFD11, error) {
@@ -0,0 +1,103 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// TuristaPasajeroExtranjero10Handler handles parsing of Turista Pasajero Extranjero 1.0 complement.
+type TuristaPasajeroExtranjero10Handler struct {
+	config HandlerConfig
+}
+
+// turistaPasajeroExtranjero10Element es el nombre del elemento raiz del complemento Turista Pasajero Extranjero 1.0.
+var turistaPasajeroExtranjero10Element = xml.Name{Space: "http://www.sat.gob.mx/TuristaPasajeroExtranjero", Local: "TuristaPasajeroExtranjero"}
+
+// NewTuristaPasajeroExtranjero10Handler creates a new TuristaPasajeroExtranjero10Handler.
+func NewTuristaPasajeroExtranjero10Handler(config HandlerConfig) *TuristaPasajeroExtranjero10Handler {
+	return &TuristaPasajeroExtranjero10Handler{config: config}
+}
+
+// ProcessTuristaPasajeroExtranjeroElement processes the TuristaPasajeroExtranjero element from an existing decoder stream.
+func (h *TuristaPasajeroExtranjero10Handler) ProcessTuristaPasajeroExtranjeroElement(se xml.StartElement, decoder *xml.Decoder) (*models.TuristaPasajeroExtranjero10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Turista Pasajero Extranjero, this handler only supports version 1.0")
+	}
+	data := &models.TuristaPasajeroExtranjero10Data{
+		Version:         version,
+		FechadeTransito: getAttrValue(se, "fechadeTransito"),
+		TipoTransito:    getAttrValue(se, "tipoTransito"),
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "datosTransito" {
+				data.DatosTransito = models.DatosTransito{
+					Via:               getAttrValue(t, "Via"),
+					TipoID:            getAttrValue(t, "TipoId"),
+					NumeroID:          getAttrValue(t, "NumeroId"),
+					Nacionalidad:      getAttrValue(t, "Nacionalidad"),
+					EmpresaTransporte: helpers.CompactString(h.config.EscDelimiters, getAttrValue(t, "EmpresaTransporte")),
+					IDTransporte:      getAttrValueOrDefault(t, "IdTransporte", h.config.EmptyChar),
+				}
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+// TransformFromBytes parses a Turista Pasajero Extranjero 1.0 XML byte slice.
+func (h *TuristaPasajeroExtranjero10Handler) TransformFromBytes(xmlBytes []byte) (*models.TuristaPasajeroExtranjero10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Turista Pasajero Extranjero 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *TuristaPasajeroExtranjero10Handler) TransformFromString(xmlString string) (*models.TuristaPasajeroExtranjero10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *TuristaPasajeroExtranjero10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *TuristaPasajeroExtranjero10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessTuristaPasajeroExtranjeroElement.
+func (h *TuristaPasajeroExtranjero10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessTuristaPasajeroExtranjeroElement(se, decoder))
+}
+
+// TransformFromReader parses a Turista Pasajero Extranjero 1.0 XML document read from r.
+func (h *TuristaPasajeroExtranjero10Handler) TransformFromReader(r io.Reader) (*models.TuristaPasajeroExtranjero10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, turistaPasajeroExtranjero10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessTuristaPasajeroExtranjeroElement(se, decoder)
+}
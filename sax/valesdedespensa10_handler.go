@@ -0,0 +1,111 @@
+package sax
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/helpers"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ValesDeDespensa10Handler handles parsing of Vales de Despensa 1.0 complement.
+type ValesDeDespensa10Handler struct {
+	config HandlerConfig
+}
+
+// valesDeDespensa10Element es el nombre del elemento raiz del complemento Vales de Despensa 1.0.
+var valesDeDespensa10Element = xml.Name{Space: "http://www.sat.gob.mx/valesdedespensa", Local: "ValesDeDespensa"}
+
+// NewValesDeDespensa10Handler creates a new ValesDeDespensa10Handler.
+func NewValesDeDespensa10Handler(config HandlerConfig) *ValesDeDespensa10Handler {
+	return &ValesDeDespensa10Handler{config: config}
+}
+
+// ProcessValesDeDespensaElement processes the ValesDeDespensa element from an existing decoder stream.
+func (h *ValesDeDespensa10Handler) ProcessValesDeDespensaElement(se xml.StartElement, decoder *xml.Decoder) (*models.ValesDeDespensa10Data, error) {
+	version := strings.TrimSpace(getAttrValue(se, "version"))
+	if version != "1.0" {
+		return nil, errors.New("incorrect type of Vales de Despensa, this handler only supports version 1.0")
+	}
+	data := &models.ValesDeDespensa10Data{
+		Version:          version,
+		TipoOperacion:    getAttrValue(se, "tipoOperacion"),
+		RegistroPatronal: getAttrValueOrDefault(se, "registroPatronal", h.config.EmptyChar),
+		NumeroDeCuenta:   getAttrValue(se, "numeroDeCuenta"),
+		Total:            getAttrValue(se, "total"),
+		Conceptos:        []models.ValesDeDespensaConcepto{},
+	}
+
+	scope := newElementScope(se)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
+			scope.enter()
+			if t.Name.Local == "Concepto" {
+				data.Conceptos = append(data.Conceptos, h.transformConcepto(t))
+			}
+		case xml.EndElement:
+			if scope.closes() {
+				return data, nil
+			}
+		}
+	}
+}
+
+func (h *ValesDeDespensa10Handler) transformConcepto(se xml.StartElement) models.ValesDeDespensaConcepto {
+	return models.ValesDeDespensaConcepto{
+		Identificador:      getAttrValue(se, "identificador"),
+		Fecha:              getAttrValue(se, "fecha"),
+		Rfc:                getAttrValue(se, "rfc"),
+		Curp:               strings.ToUpper(getAttrValue(se, "curp")),
+		Nombre:             helpers.CompactString(h.config.EscDelimiters, getAttrValue(se, "nombre")),
+		NumSeguridadSocial: getAttrValueOrDefault(se, "numSeguridadSocial", h.config.EmptyChar),
+		Importe:            getAttrValue(se, "importe"),
+	}
+}
+
+// TransformFromBytes parses a Vales de Despensa 1.0 XML byte slice.
+func (h *ValesDeDespensa10Handler) TransformFromBytes(xmlBytes []byte) (*models.ValesDeDespensa10Data, error) {
+	return h.TransformFromString(string(xmlBytes))
+}
+
+// TransformFromString parses a Vales de Despensa 1.0 XML string, either the complement alone or a whole document containing it.
+func (h *ValesDeDespensa10Handler) TransformFromString(xmlString string) (*models.ValesDeDespensa10Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
+
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *ValesDeDespensa10Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
+
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *ValesDeDespensa10Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
+
+// ProcessElement implements ComplementHandler with ProcessValesDeDespensaElement.
+func (h *ValesDeDespensa10Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessValesDeDespensaElement(se, decoder))
+}
+
+// TransformFromReader parses a Vales de Despensa 1.0 XML document read from r.
+func (h *ValesDeDespensa10Handler) TransformFromReader(r io.Reader) (*models.ValesDeDespensa10Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, valesDeDespensa10Element)
+	if err != nil {
+		return nil, err
+	}
+	return h.ProcessValesDeDespensaElement(se, decoder)
+}
@@ -13,6 +13,9 @@ type VentaVehiculos11Handler struct {
 	config HandlerConfig
 }
 
+// ventaVehiculos11Element es el nombre del elemento raiz del complemento VentaVehiculos 1.1.
+var ventaVehiculos11Element = xml.Name{Space: "http://www.sat.gob.mx/ventavehiculos", Local: "VentaVehiculos"}
+
 func NewVentaVehiculos11Handler(config HandlerConfig) *VentaVehiculos11Handler {
 	return &VentaVehiculos11Handler{config: config}
 }
@@ -30,6 +33,7 @@ func (h *VentaVehiculos11Handler) ProcessVentaVehiculosElement(se xml.StartEleme
 		Partes:              []models.Parte{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err == io.EOF {
@@ -40,14 +44,20 @@ func (h *VentaVehiculos11Handler) ProcessVentaVehiculosElement(se xml.StartEleme
 		}
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			switch t.Name.Local {
 			case "InformacionAduanera":
 				data.InformacionAduanera = append(data.InformacionAduanera, h.transformInformacionAduanera(t, decoder))
 			case "Parte":
 				data.Partes = append(data.Partes, h.transformParte(t, decoder))
+			default:
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "VentaVehiculos" {
+			if scope.closes() {
 				return data, nil
 			}
 		}
@@ -55,50 +65,49 @@ func (h *VentaVehiculos11Handler) ProcessVentaVehiculosElement(se xml.StartEleme
 	return data, nil
 }
 
-func (h *VentaVehiculos11Handler) transformBytes(xmlBytes []byte) (*models.VentaVehiculos11Data, error) {
-	return h.transformString(string(xmlBytes))
+// TransformFromBytes parses a VentaVehiculos 1.1 XML byte slice.
+func (h *VentaVehiculos11Handler) TransformFromBytes(xmlBytes []byte) (*models.VentaVehiculos11Data, error) {
+	return h.TransformFromString(string(xmlBytes))
 }
 
-func (h *VentaVehiculos11Handler) transformString(xmlString string) (*models.VentaVehiculos11Data, error) {
-	data := &models.VentaVehiculos11Data{
-		InformacionAduanera: []models.InformacionAduanera{},
-		Partes:              []models.Parte{},
-	}
+// TransformFromString parses a VentaVehiculos 1.1 XML string, either the complement alone or a whole document containing it.
+func (h *VentaVehiculos11Handler) TransformFromString(xmlString string) (*models.VentaVehiculos11Data, error) {
+	return h.TransformFromReader(strings.NewReader(xmlString))
+}
 
-	decoder := xml.NewDecoder(strings.NewReader(xmlString))
+// ParseBytes implements ComplementHandler with TransformFromBytes.
+func (h *VentaVehiculos11Handler) ParseBytes(xmlBytes []byte) (interface{}, error) {
+	return complementResult(h.TransformFromBytes(xmlBytes))
+}
 
-	for {
-		token, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+// ParseReader implements ComplementHandler with TransformFromReader.
+func (h *VentaVehiculos11Handler) ParseReader(r io.Reader) (interface{}, error) {
+	return complementResult(h.TransformFromReader(r))
+}
 
-		switch se := token.(type) {
-		case xml.StartElement:
-			switch se.Name.Local {
-			case "InformacionAduanera":
-				data.InformacionAduanera = append(data.InformacionAduanera, h.transformInformacionAduanera(se, decoder))
-			case "Parte":
-				data.Partes = append(data.Partes, h.transformParte(se, decoder))
-			}
-		case xml.EndElement:
-			if se.Name.Local == "VentaVehiculos" {
-				return data, nil
-			}
-		}
+// ProcessElement implements ComplementHandler with ProcessVentaVehiculosElement.
+func (h *VentaVehiculos11Handler) ProcessElement(se xml.StartElement, decoder *xml.Decoder) (interface{}, error) {
+	return complementResult(h.ProcessVentaVehiculosElement(se, decoder))
+}
+
+// TransformFromReader parses a VentaVehiculos 1.1 XML document read from r.
+func (h *VentaVehiculos11Handler) TransformFromReader(r io.Reader) (*models.VentaVehiculos11Data, error) {
+	decoder := xml.NewDecoder(r)
+	se, err := findElement(decoder, ventaVehiculos11Element)
+	if err != nil {
+		return nil, err
 	}
-	return data, nil
+	return h.ProcessVentaVehiculosElement(se, decoder)
 }
 
 func (h *VentaVehiculos11Handler) transformInformacionAduanera(se xml.StartElement, decoder *xml.Decoder) models.InformacionAduanera {
-	return models.InformacionAduanera{
+	informacionAduanera := models.InformacionAduanera{
 		Numero: getAttrValue(se, "Numero"),
 		Fecha:  getAttrValue(se, "Fecha"),
 		Aduana: getAttrValue(se, "Aduana"),
 	}
+	skipElement(decoder)
+	return informacionAduanera
 }
 
 func (h *VentaVehiculos11Handler) transformParte(se xml.StartElement, decoder *xml.Decoder) models.Parte {
@@ -112,6 +121,7 @@ func (h *VentaVehiculos11Handler) transformParte(se xml.StartElement, decoder *x
 		InformacionAduanera: []models.InformacionAduanera{},
 	}
 
+	scope := newElementScope(se)
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -120,11 +130,17 @@ func (h *VentaVehiculos11Handler) transformParte(se xml.StartElement, decoder *x
 
 		switch t := token.(type) {
 		case xml.StartElement:
+			if !scope.owns(t) {
+				skipElement(decoder)
+				continue
+			}
 			if t.Name.Local == "InformacionAduanera" {
 				parte.InformacionAduanera = append(parte.InformacionAduanera, h.transformInformacionAduanera(t, decoder))
+			} else {
+				scope.enter()
 			}
 		case xml.EndElement:
-			if t.Name.Local == "Parte" {
+			if scope.closes() {
 				return parte
 			}
 		}
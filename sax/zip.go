@@ -0,0 +1,149 @@
+package sax
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/metadata"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+// ZipEntry es el resultado de parsear un XML dentro de un paquete zip.
+type ZipEntry struct {
+	// Name es la ruta del XML dentro del zip.
+	Name string
+	Data *models.CFDI40Data
+	// Metadata es el registro de metadata con el mismo UUID, si el paquete lo incluye.
+	Metadata *metadata.Record
+	Err      error
+}
+
+// ZipIterator recorre los XML de un paquete zip de la descarga masiva del SAT sin extraerlos a disco.
+type ZipIterator struct {
+	handler  *CFDI40Handler
+	reader   *zip.ReadCloser
+	files    []*zip.File
+	pos      int
+	entry    ZipEntry
+	metadata map[string]metadata.Record
+	// metadataErrors son las lineas de metadata que no se pudieron leer.
+	metadataErrors []error
+}
+
+// TransformFromZip opens a zip package and returns an iterator over its XML entries.
+// The metadata TXT files of the package are parsed up front and joined to each entry by UUID.
+// Malformed metadata lines are skipped and reported by MetadataErrors.
+// The iterator must be closed after use.
+func (h *CFDI40Handler) TransformFromZip(path string) (*ZipIterator, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening zip: %w", err)
+	}
+
+	it := &ZipIterator{
+		handler:  h,
+		reader:   reader,
+		metadata: map[string]metadata.Record{},
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.ToLower(file.Name)
+		switch {
+		case strings.HasSuffix(name, ".xml"):
+			it.files = append(it.files, file)
+		case strings.HasSuffix(name, ".txt"):
+			if err := it.readMetadata(file); err != nil {
+				reader.Close()
+				return nil, fmt.Errorf("error reading %s: %w", file.Name, err)
+			}
+		}
+	}
+	sort.Slice(it.files, func(i, j int) bool { return it.files[i].Name < it.files[j].Name })
+
+	return it, nil
+}
+
+func (it *ZipIterator) readMetadata(file *zip.File) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	reader := metadata.NewReader(rc)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *metadata.ParseError
+		if errors.As(err, &parseErr) {
+			it.metadataErrors = append(it.metadataErrors, fmt.Errorf("%s: %w", file.Name, err))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		it.metadata[record.UUID] = record
+	}
+}
+
+// Next parses the next XML entry. It returns false when there are no more entries.
+func (it *ZipIterator) Next() bool {
+	if it.pos >= len(it.files) {
+		return false
+	}
+	file := it.files[it.pos]
+	it.pos++
+
+	it.entry = ZipEntry{Name: file.Name}
+	it.entry.Data, it.entry.Err = it.transformFile(file)
+	if it.entry.Data != nil && len(it.entry.Data.TFD11) > 0 {
+		if record, ok := it.metadata[it.entry.Data.TFD11[0].UUID]; ok {
+			it.entry.Metadata = &record
+		}
+	}
+	return true
+}
+
+func (it *ZipIterator) transformFile(file *zip.File) (*models.CFDI40Data, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return it.handler.TransformFromString(string(content))
+}
+
+// Entry returns the entry parsed by the last call to Next.
+func (it *ZipIterator) Entry() ZipEntry {
+	return it.entry
+}
+
+// Metadata returns every metadata record of the package keyed by UUID.
+func (it *ZipIterator) Metadata() map[string]metadata.Record {
+	return it.metadata
+}
+
+// MetadataErrors returns the metadata lines that were skipped because they could not be parsed.
+// Each error wraps a *metadata.ParseError and is prefixed with the name of the TXT file.
+func (it *ZipIterator) MetadataErrors() []error {
+	return it.metadataErrors
+}
+
+// Close closes the zip package.
+func (it *ZipIterator) Close() error {
+	return it.reader.Close()
+}
@@ -0,0 +1,36 @@
+// Command gen escribe los archivos de esquema de este paquete para go generate, sin compilar
+// la herramienta gocfdi completa.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sucksens/gocfdi-transform/schema"
+)
+
+func main() {
+	format := flag.String("format", "jsonschema", "output format: jsonschema or openapi")
+	output := flag.String("o", "", "output file")
+	flag.Parse()
+
+	generate := map[string]func() ([]byte, error){
+		"jsonschema": schema.JSONSchema,
+		"openapi":    schema.OpenAPIComponents,
+	}[*format]
+	if generate == nil || *output == "" || flag.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: gen -format jsonschema|openapi -o file")
+		os.Exit(2)
+	}
+
+	content, err := generate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error generating schema: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, content, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing schema: %v\n", err)
+		os.Exit(1)
+	}
+}
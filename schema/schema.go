@@ -0,0 +1,185 @@
+// Package schema genera el JSON Schema y los componentes OpenAPI de los modelos a partir
+// de los tags json de sus estructuras, para publicar el contrato de los CFDI parseados.
+//
+// Los archivos cfdi.schema.json y openapi.json de este directorio se generan con:
+//
+//	go generate ./schema
+package schema
+
+//go:generate go run ./internal/gen -format jsonschema -o cfdi.schema.json
+//go:generate go run ./internal/gen -format openapi -o openapi.json
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+const (
+	// SchemaID es el $id del JSON Schema publicado.
+	SchemaID = "https://github.com/sucksens/gocfdi-transform/schema/cfdi.schema.json"
+	// OpenAPIVersion es la version de OpenAPI del archivo de componentes, compatible con JSON Schema 2020-12.
+	OpenAPIVersion = "3.1.0"
+)
+
+// Roots son los modelos de primer nivel incluidos en los esquemas. CFDI40Data alcanza a todos
+// los complementos que se parsean dentro de un CFDI 4.0; Pagos10Data se parsea por separado.
+var Roots = []interface{}{
+	models.CFDI40Data{},
+	models.Pagos10Data{},
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of CFDI40Data, with every model in $defs.
+func JSONSchema() ([]byte, error) {
+	defs := definitions("#/$defs/")
+	doc := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "CFDI40Data",
+		"$ref":    "#/$defs/CFDI40Data",
+		"$defs":   defs,
+	}
+	return marshal(doc)
+}
+
+// OpenAPIComponents returns an OpenAPI document with every model in components.schemas.
+func OpenAPIComponents() ([]byte, error) {
+	doc := map[string]interface{}{
+		"openapi": OpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "gocfdi-transform models",
+			"version": "1.0.0",
+		},
+		"paths": map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": definitions("#/components/schemas/"),
+		},
+	}
+	return marshal(doc)
+}
+
+func marshal(doc map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// definitions returns the schema of every struct reachable from Roots, keyed by type name.
+func definitions(refPrefix string) map[string]interface{} {
+	g := &generator{refPrefix: refPrefix, defs: map[string]interface{}{}}
+	for _, root := range Roots {
+		g.define(reflect.TypeOf(root))
+	}
+	return g.defs
+}
+
+type generator struct {
+	refPrefix string
+	defs      map[string]interface{}
+}
+
+// define adds the schema of struct t to defs and returns a reference to it.
+func (g *generator) define(t reflect.Type) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": g.refPrefix + t.Name()}
+	if _, ok := g.defs[t.Name()]; ok {
+		return ref
+	}
+	// Reserve the name before walking the fields so recursive types terminate
+	g.defs[t.Name()] = nil
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	g.fields(t, properties, &required)
+
+	def := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		def["required"] = required
+	}
+	g.defs[t.Name()] = def
+	return ref
+}
+
+// fields adds the properties of struct t as encoding/json would marshal them,
+// inlining embedded structs without a json name.
+func (g *generator) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.fields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitempty := strings.Contains(","+options+",", ",omitempty,")
+		properties[name] = g.fieldSchema(field.Type, !omitempty)
+		// encoding/json never omits a struct value, even with omitempty
+		if !omitempty || field.Type.Kind() == reflect.Struct {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// fieldSchema returns the schema of a field of type t. Nil slices, maps and pointers are
+// marshaled as null unless the field is omitempty.
+func (g *generator) fieldSchema(t reflect.Type, nullable bool) map[string]interface{} {
+	schema := g.typeSchema(t)
+	if !nullable {
+		return schema
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		schema["type"] = []string{schema["type"].(string), "null"}
+	case reflect.Pointer:
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	}
+	return schema
+}
+
+func (g *generator) typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.Struct:
+		return g.define(t)
+	}
+	// interface{} and any other kind accept any JSON value
+	return map[string]interface{}{}
+}
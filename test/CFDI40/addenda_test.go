@@ -0,0 +1,114 @@
+package cfdi40_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const addendaXML = `<cfdi:Addenda>
+        <ped:Pedido xmlns:ped="http://www.ejemplo.com/pedido" Numero="PO-778">
+            <ped:Partida Linea="1">ART-01</ped:Partida>
+            <ped:Partida Linea="2">ART-02</ped:Partida>
+        </ped:Pedido>
+        <Referencia>REF-99</Referencia>
+    </cfdi:Addenda>
+</cfdi:Comprobante>`
+
+func cfdiWithAddenda(t *testing.T) string {
+	t.Helper()
+	content, err := os.ReadFile("../recursos/cfdi40.xml")
+	require.NoError(t, err)
+	return strings.Replace(string(content), "</cfdi:Comprobante>", addendaXML, 1)
+}
+
+func TestCFDI40Addenda(t *testing.T) {
+	xmlStr := cfdiWithAddenda(t)
+
+	t.Run("Sin UseAddendas solo se registran los nombres", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(xmlStr)
+		require.NoError(t, err)
+		assert.Equal(t, "Pedido Referencia", data.CFDI40.Addendas)
+		assert.Empty(t, data.Addenda)
+	})
+
+	t.Run("Contenido crudo y aplanado por defecto", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseAddendas().TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.Addenda, 2)
+
+		pedido := data.Addenda[0]
+		assert.Equal(t, "http://www.ejemplo.com/pedido", pedido.Namespace)
+		assert.Equal(t, "Pedido", pedido.Local)
+		assert.True(t, strings.HasPrefix(string(pedido.Raw), `<ped:Pedido xmlns:ped=`))
+		assert.True(t, strings.HasSuffix(string(pedido.Raw), `</ped:Pedido>`))
+		assert.Equal(t, map[string]string{
+			"/Pedido/@Numero":           "PO-778",
+			"/Pedido/Partida/@Linea":    "1",
+			"/Pedido/Partida":           "ART-01",
+			"/Pedido/Partida[2]/@Linea": "2",
+			"/Pedido/Partida[2]":        "ART-02",
+		}, pedido.Data)
+
+		assert.Equal(t, "<Referencia>REF-99</Referencia>", string(data.Addenda[1].Raw))
+		assert.Equal(t, map[string]string{"/Referencia": "REF-99"}, data.Addenda[1].Data)
+	})
+
+	t.Run("Parser registrado por namespace", func(t *testing.T) {
+		parser := sax.AddendaParserFunc(func(raw []byte) (interface{}, error) {
+			return len(raw), nil
+		})
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).
+			UseAddendas().
+			RegisterAddendaParser("{http://www.ejemplo.com/pedido}Pedido", parser).
+			TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.Addenda, 2)
+		assert.Equal(t, len(data.Addenda[0].Raw), data.Addenda[0].Data)
+		assert.IsType(t, map[string]string{}, data.Addenda[1].Data)
+	})
+
+	t.Run("Los namespaces declarados en el Comprobante se conservan", func(t *testing.T) {
+		recepcion := strings.Replace(cfdiWithAddenda(t), "<cfdi:Comprobante ", `<cfdi:Comprobante xmlns:rec="urn:ejemplo:recepcion" `, 1)
+		recepcion = strings.Replace(recepcion, "<Referencia>", `<rec:Recepcion Folio="R-1"/><Referencia>`, 1)
+
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseAddendas().TransformFromString(recepcion)
+		require.NoError(t, err)
+		require.Len(t, data.Addenda, 3)
+
+		raw := data.Addenda[1]
+		assert.Equal(t, "urn:ejemplo:recepcion", raw.Namespace)
+		assert.Equal(t, `<rec:Recepcion xmlns:rec="urn:ejemplo:recepcion" Folio="R-1"/>`, string(raw.Raw))
+
+		var element struct {
+			XMLName xml.Name
+			Folio   string `xml:"Folio,attr"`
+		}
+		require.NoError(t, xml.Unmarshal(raw.Raw, &element))
+		assert.Equal(t, xml.Name{Space: "urn:ejemplo:recepcion", Local: "Recepcion"}, element.XMLName)
+		assert.Equal(t, "R-1", element.Folio)
+
+		assert.Equal(t, "<Referencia>REF-99</Referencia>", string(data.Addenda[2].Raw))
+	})
+
+	t.Run("El error del parser se registra en la addenda", func(t *testing.T) {
+		parser := sax.AddendaParserFunc(func(raw []byte) (interface{}, error) {
+			return nil, errors.New("pedido invalido")
+		})
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).
+			UseAddendas().
+			RegisterAddendaParser("Pedido", parser).
+			TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.Addenda, 2)
+		assert.Nil(t, data.Addenda[0].Data)
+		assert.Equal(t, "pedido invalido", data.Addenda[0].Error)
+		assert.Empty(t, data.Addenda[1].Error)
+	})
+}
@@ -0,0 +1,155 @@
+package cfdi40_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const nominaFragment = `<nomina12:Nomina xmlns:nomina12="http://www.sat.gob.mx/nomina12" Version="1.2" TipoNomina="O" FechaPago="2025-01-31" FechaInicialPago="2025-01-16" FechaFinalPago="2025-01-31" NumDiasPagados="16" TotalPercepciones="10000.00" TotalDeducciones="950.00">
+	<nomina12:Receptor Curp="XOJI740919MJCDMN05" TipoContrato="01" TipoRegimen="02" NumEmpleado="120" PeriodicidadPago="04" ClaveEntFed="JAL" />
+	<nomina12:Deducciones TotalImpuestosRetenidos="950.00">
+		<nomina12:Deduccion TipoDeduccion="002" Clave="002" Concepto="ISR" Importe="950.00" />
+	</nomina12:Deducciones>
+</nomina12:Nomina>`
+
+const ventaVehiculosFragment = `<ventavehiculos:VentaVehiculos xmlns:ventavehiculos="http://www.sat.gob.mx/ventavehiculos" Version="1.1" ClaveVehicular="123456" Niv="ABC1234567890">
+	<ventavehiculos:Parte Cantidad="1" Descripcion="Parte1" />
+</ventavehiculos:VentaVehiculos>`
+
+func TestComplementHandlersPublicAPI(t *testing.T) {
+	config := sax.NewDefaultConfig()
+
+	t.Run("Todos los manejadores cumplen ComplementHandler", func(t *testing.T) {
+		handlers := []sax.ComplementHandler{
+			sax.NewTFD11Handler(config),
+			sax.NewNomina12Handler(config),
+			sax.NewPagos20Handler(config),
+			sax.NewPagos10Handler(config),
+			sax.NewVentaVehiculos11Handler(config),
+			sax.NewLeyendasFiscales10Handler(config),
+			sax.NewDonatarias11Handler(config),
+			sax.NewDetallistaHandler(config),
+			sax.NewIEDU10Handler(config),
+			sax.NewServicioParcial10Handler(config),
+			sax.NewTuristaPasajeroExtranjero10Handler(config),
+			sax.NewValesDeDespensa10Handler(config),
+			sax.NewIngresosHidrocarburos10Handler(config),
+			sax.NewGastosHidrocarburos10Handler(config),
+		}
+		assert.Len(t, sax.DefaultCFDI40Complements(), len(handlers))
+	})
+
+	t.Run("Fragmento de Nomina desde bytes y reader", func(t *testing.T) {
+		handler := sax.NewNomina12Handler(config)
+
+		nomina, err := handler.TransformFromBytes([]byte(nominaFragment))
+		require.NoError(t, err)
+		assert.Equal(t, "O", nomina.TipoNomina)
+		assert.Equal(t, "XOJI740919MJCDMN05", nomina.Receptor.Curp)
+		require.Len(t, nomina.Deducciones.Deduccion, 1)
+
+		fromReader, err := handler.TransformFromReader(strings.NewReader(nominaFragment))
+		require.NoError(t, err)
+		assert.Equal(t, nomina, fromReader)
+
+		var generic sax.ComplementHandler = handler
+		parsed, err := generic.ParseBytes([]byte(nominaFragment))
+		require.NoError(t, err)
+		assert.Equal(t, nomina, parsed)
+		parsed, err = generic.ParseReader(strings.NewReader(nominaFragment))
+		require.NoError(t, err)
+		assert.Equal(t, nomina, parsed)
+	})
+
+	t.Run("Fragmento sin declaracion de namespace", func(t *testing.T) {
+		// El prefijo se declaro en un ancestro que no forma parte del fragmento
+		nomina, err := sax.NewNomina12Handler(config).TransformFromString(strings.Replace(nominaFragment, ` xmlns:nomina12="http://www.sat.gob.mx/nomina12"`, "", 1))
+		require.NoError(t, err)
+		assert.Equal(t, "XOJI740919MJCDMN05", nomina.Receptor.Curp)
+		require.Len(t, nomina.Deducciones.Deduccion, 1)
+
+		pagos, err := sax.NewPagos20Handler(config).TransformFromBytes([]byte(`<pago20:Pagos Version="2.0">
+	<pago20:Totales MontoTotalPagos="100.00"/>
+	<pago20:Pago FechaPago="2025-01-15T12:00:00" FormaDePagoP="03" MonedaP="MXN" TipoCambioP="1" Monto="100.00"/>
+</pago20:Pagos>`))
+		require.NoError(t, err)
+		assert.Equal(t, "100.00", pagos.Totales.MontoTotalPagos)
+		assert.Len(t, pagos.Pagos, 1)
+
+		_, err = sax.NewPagos20Handler(config).TransformFromString(`<Pagos Version="2.0"/>`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fragmento de TFD con el modelo concreto", func(t *testing.T) {
+		fragment := `<tfd:TimbreFiscalDigital xmlns:tfd="http://www.sat.gob.mx/TimbreFiscalDigital" Version="1.1" UUID="a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c" FechaTimbrado="2025-01-15T10:30:01" RfcProvCertif="AAA010101AAA" NoCertificadoSAT="30001000000500003456"/>`
+		handler := sax.NewTFD11Handler(config)
+
+		tfd, err := handler.TransformFromBytes([]byte(fragment))
+		require.NoError(t, err)
+		assert.Equal(t, "A3C6A0D7-8F4B-4E2A-9B5C-1D8E9F7A6B2C", tfd.UUID)
+		assert.Equal(t, "AAA010101AAA", tfd.RfcProvCert)
+
+		parsed, err := handler.ParseBytes([]byte(fragment))
+		require.NoError(t, err)
+		assert.Equal(t, tfd, parsed)
+
+		tfd, err = handler.TransformFromBytes([]byte(strings.Replace(fragment, `Version="1.1"`, `Version="1.0"`, 1)))
+		assert.Error(t, err)
+		assert.Nil(t, tfd)
+	})
+
+	t.Run("Fragmento de VentaVehiculos", func(t *testing.T) {
+		data, err := sax.NewVentaVehiculos11Handler(config).TransformFromString(ventaVehiculosFragment)
+		require.NoError(t, err)
+		assert.Equal(t, "ABC1234567890", data.Niv)
+		assert.Len(t, data.Partes, 1)
+	})
+
+	t.Run("El elemento raiz se busca por namespace", func(t *testing.T) {
+		foreign := strings.ReplaceAll(nominaFragment, "http://www.sat.gob.mx/nomina12", "urn:example:nomina")
+		data, err := sax.NewNomina12Handler(config).TransformFromBytes([]byte(foreign))
+		assert.Error(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("Un error no regresa un puntero nil tipado", func(t *testing.T) {
+		invalid := strings.Replace(nominaFragment, `Version="1.2"`, `Version="1.1"`, 1)
+		data, err := sax.NewNomina12Handler(config).ParseBytes([]byte(invalid))
+		assert.Error(t, err)
+		assert.True(t, data == nil)
+	})
+
+	t.Run("ProcessElement consume el complemento hasta su cierre", func(t *testing.T) {
+		registry := sax.DefaultCFDI40Complements()
+		decoder := xml.NewDecoder(strings.NewReader("<root>" + nominaFragment + ventaVehiculosFragment + "</root>"))
+
+		var results []interface{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				break
+			}
+			se, ok := token.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			factory, ok := registry["{"+se.Name.Space+"}"+se.Name.Local]
+			if !ok {
+				continue
+			}
+			result, err := factory(config).ProcessElement(se, decoder)
+			require.NoError(t, err)
+			results = append(results, result)
+		}
+
+		require.Len(t, results, 2)
+		assert.IsType(t, &models.Nomina12Data{}, results[0])
+		assert.IsType(t, &models.VentaVehiculos11Data{}, results[1])
+	})
+}
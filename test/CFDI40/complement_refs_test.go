@@ -0,0 +1,46 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestCFDI40ComplementRefs(t *testing.T) {
+	t.Run("Solo complementos de primer nivel", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromFile("../recursos/cfdi40_pagos.xml")
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.ComplementRef{
+			{Namespace: "http://www.sat.gob.mx/Pagos20", Local: "Pagos", Version: "2.0"},
+			{Namespace: "http://www.sat.gob.mx/TimbreFiscalDigital", Local: "TimbreFiscalDigital", Version: "1.1"},
+		}, data.CFDI40.ComplementosRefs)
+		assert.Equal(t, "Pagos TimbreFiscalDigital", data.CFDI40.Complementos)
+	})
+
+	t.Run("Complementos conocidos y desconocidos", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(cfdiWithUnknownComplements(t))
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.ComplementRef{
+			{Namespace: "http://www.sat.gob.mx/TimbreFiscalDigital", Local: "TimbreFiscalDigital", Version: "1.1"},
+			{Namespace: "http://www.sat.gob.mx/leyendasFiscales", Local: "LeyendasFiscales", Version: "1.0"},
+			{Namespace: "http://www.sat.gob.mx/donat", Local: "Donatarias", Version: "1.1"},
+		}, data.CFDI40.ComplementosRefs)
+		// Los hijos de un complemento no procesado no se registran
+		assert.Equal(t, "TimbreFiscalDigital LeyendasFiscales Donatarias", data.CFDI40.Complementos)
+	})
+
+	t.Run("Addendas de primer nivel", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(cfdiWithAddenda(t))
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.ComplementRef{
+			{Namespace: "http://www.ejemplo.com/pedido", Local: "Pedido"},
+			{Local: "Referencia"},
+		}, data.CFDI40.AddendasRefs)
+	})
+}
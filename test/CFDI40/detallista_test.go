@@ -0,0 +1,143 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestDetallistaHandler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:detallista="http://www.sat.gob.mx/detallista" Version="4.0">
+		<cfdi:Complemento>
+			<detallista:detallista type="SimpleInvoiceType" contentVersion="1.3.1" documentStructureVersion="AMC8.1" documentStatus="ORIGINAL">
+				<detallista:requestForPaymentIdentification>
+					<detallista:entityType>INVOICE</detallista:entityType>
+				</detallista:requestForPaymentIdentification>
+				<detallista:specialInstruction code="ZZZ">
+					<detallista:text>MIL CIENTO SESENTA PESOS</detallista:text>
+					<detallista:text>00/100 M.N.</detallista:text>
+				</detallista:specialInstruction>
+				<detallista:orderIdentification>
+					<detallista:referenceIdentification type="ON">4500012345</detallista:referenceIdentification>
+					<detallista:ReferenceDate>2025-01-10</detallista:ReferenceDate>
+				</detallista:orderIdentification>
+				<detallista:AdditionalInformation>
+					<detallista:referenceIdentification type="ATZ">REC-77</detallista:referenceIdentification>
+				</detallista:AdditionalInformation>
+				<detallista:DeliveryNote>
+					<detallista:referenceIdentification>ENT-1</detallista:referenceIdentification>
+					<detallista:ReferenceDate>2025-01-12</detallista:ReferenceDate>
+				</detallista:DeliveryNote>
+				<detallista:buyer>
+					<detallista:gln>7504000000000</detallista:gln>
+					<detallista:contactInformation>
+						<detallista:personOrDepartmentName>
+							<detallista:text>COMPRAS</detallista:text>
+						</detallista:personOrDepartmentName>
+					</detallista:contactInformation>
+				</detallista:buyer>
+				<detallista:seller>
+					<detallista:gln>7501000000000</detallista:gln>
+					<detallista:alternatePartyIdentification type="SELLER_ASSIGNED_IDENTIFIER_FOR_A_PARTY">PROV-123</detallista:alternatePartyIdentification>
+				</detallista:seller>
+				<detallista:currency currencyISOCode="MXN">
+					<detallista:currencyFunction>BILLING_CURRENCY</detallista:currencyFunction>
+					<detallista:rateOfChange>1.00</detallista:rateOfChange>
+				</detallista:currency>
+				<detallista:paymentTerms paymentTermsEvent="DATE_OF_INVOICE" PaymentTermsRelationTime="REFERENCE_AFTER">
+					<detallista:netPayment netPaymentTermsType="BASIC_NET">
+						<detallista:paymentTimePeriod>
+							<detallista:timePeriodDue timePeriod="DAYS">
+								<detallista:value>30</detallista:value>
+							</detallista:timePeriodDue>
+						</detallista:paymentTimePeriod>
+					</detallista:netPayment>
+				</detallista:paymentTerms>
+				<detallista:lineItem type="SimpleInvoiceLineItemType" number="1">
+					<detallista:tradeItemIdentification>
+						<detallista:gtin>07501234567890</detallista:gtin>
+					</detallista:tradeItemIdentification>
+					<detallista:tradeItemDescriptionInformation language="ES">
+						<detallista:longText>CAJA DE GALLETAS</detallista:longText>
+					</detallista:tradeItemDescriptionInformation>
+					<detallista:invoicedQuantity unitOfMeasure="CS">10</detallista:invoicedQuantity>
+					<detallista:grossPrice>
+						<detallista:Amount>100.00</detallista:Amount>
+					</detallista:grossPrice>
+					<detallista:netPrice>
+						<detallista:Amount>100.00</detallista:Amount>
+					</detallista:netPrice>
+					<detallista:totalLineAmount>
+						<detallista:grossAmount>
+							<detallista:Amount>1000.00</detallista:Amount>
+						</detallista:grossAmount>
+						<detallista:netAmount>
+							<detallista:Amount>1000.00</detallista:Amount>
+						</detallista:netAmount>
+					</detallista:totalLineAmount>
+				</detallista:lineItem>
+				<detallista:totalAmount>
+					<detallista:Amount>1160.00</detallista:Amount>
+				</detallista:totalAmount>
+				<detallista:TotalAllowanceCharge allowanceOrChargeType="ALLOWANCE">
+					<detallista:specialServicesType>AJ</detallista:specialServicesType>
+					<detallista:Amount>0.00</detallista:Amount>
+				</detallista:TotalAllowanceCharge>
+			</detallista:detallista>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseDetallista().TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.Detallista, 1)
+	d := data.Detallista[0]
+
+	t.Run("Encabezado", func(t *testing.T) {
+		assert.Equal(t, "SimpleInvoiceType", d.Type)
+		assert.Equal(t, "1.3.1", d.ContentVersion)
+		assert.Equal(t, "ORIGINAL", d.DocumentStatus)
+		assert.Equal(t, "INVOICE", d.EntityType)
+		assert.Equal(t, []models.DetallistaSpecialInstruction{{Code: "ZZZ", Text: "MIL CIENTO SESENTA PESOS 00/100 M.N."}}, d.SpecialInstructions)
+		assert.Equal(t, models.DetallistaReference{ReferenceIdentification: "4500012345", ReferenceDate: "2025-01-10"}, d.OrderIdentification)
+		assert.Equal(t, []models.DetallistaReferenceIdentifier{{Type: "ATZ", Value: "REC-77"}}, d.AdditionalInformation)
+		assert.Equal(t, "ENT-1", d.DeliveryNote.ReferenceIdentification)
+	})
+
+	t.Run("Comprador, vendedor y condiciones", func(t *testing.T) {
+		assert.Equal(t, "7504000000000", d.BuyerGLN)
+		assert.Equal(t, "COMPRAS", d.BuyerContact)
+		assert.Equal(t, "7501000000000", d.SellerGLN)
+		assert.Equal(t, "PROV-123", d.SellerAlternateID.Value)
+		assert.Equal(t, []models.DetallistaCurrency{{CurrencyISOCode: "MXN", CurrencyFunction: "BILLING_CURRENCY", RateOfChange: "1.00"}}, d.Currencies)
+		assert.Equal(t, models.DetallistaPaymentTerms{
+			PaymentTermsEvent:        "DATE_OF_INVOICE",
+			PaymentTermsRelationTime: "REFERENCE_AFTER",
+			NetPaymentTermsType:      "BASIC_NET",
+			TimePeriod:               "DAYS",
+			Value:                    "30",
+		}, d.PaymentTerms)
+	})
+
+	t.Run("Partidas y totales", func(t *testing.T) {
+		assert.Equal(t, []models.DetallistaLineItem{{
+			Type:             "SimpleInvoiceLineItemType",
+			Number:           "1",
+			GTIN:             "07501234567890",
+			Language:         "ES",
+			LongText:         "CAJA DE GALLETAS",
+			InvoicedQuantity: "10",
+			UnitOfMeasure:    "CS",
+			GrossPrice:       "100.00",
+			NetPrice:         "100.00",
+			TotalGrossAmount: "1000.00",
+			TotalNetAmount:   "1000.00",
+		}}, d.LineItems)
+		assert.Equal(t, "1160.00", d.TotalAmount)
+		assert.Equal(t, []models.DetallistaTotalAllowanceCharge{{AllowanceOrChargeType: "ALLOWANCE", SpecialServicesType: "AJ", Amount: "0.00"}}, d.TotalAllowanceCharges)
+	})
+}
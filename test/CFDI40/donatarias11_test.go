@@ -0,0 +1,39 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestDonatarias11Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:donat="http://www.sat.gob.mx/donat" Version="4.0">
+		<cfdi:Complemento>
+			<donat:Donatarias version="1.1" noAutorizacion="325-SAT-09-I-A-2345" fechaAutorizacion="2023-05-10" leyenda="Este comprobante ampara un donativo"/>
+			<donat:Donatarias version="1.0" noAutorizacion="OLD"/>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseDonatarias11().UseRawComplements().TransformFromString(xmlStr)
+	require.NoError(t, err)
+
+	t.Run("Parse Donatarias11", func(t *testing.T) {
+		assert.Equal(t, []models.Donatarias11Data{{
+			Version:           "1.1",
+			NoAutorizacion:    "325-SAT-09-I-A-2345",
+			FechaAutorizacion: "2023-05-10",
+			Leyenda:           "Este comprobante ampara un donativo",
+		}}, data.Donatarias11)
+	})
+
+	t.Run("Una version no soportada se conserva como complemento crudo", func(t *testing.T) {
+		require.Len(t, data.RawComplementos, 1)
+		assert.Equal(t, "1.0", data.RawComplementos[0].Version)
+		assert.Len(t, data.CFDI40.ComplementosRefs, 2)
+	})
+}
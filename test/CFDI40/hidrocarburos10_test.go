@@ -0,0 +1,96 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestIngresosHidrocarburos10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:ieeh="http://www.sat.gob.mx/IngresosHidrocarburos10" Version="4.0">
+		<cfdi:Complemento>
+			<ieeh:IngresosHidrocarburos Version="1.0" NumeroContrato="CNH-R01-L01-A1/2015" ContraprestacionPagadaOperador="150000.00" Porcentaje="35.000">
+				<ieeh:DocumentoRelacionado FolioFiscalVinculado="aaaaaaaa-1111-2222-3333-444444444444" FechaFolioFiscalVinculado="2025-01-05" Mes="01"/>
+				<ieeh:DocumentoRelacionado FolioFiscalVinculado="BBBBBBBB-1111-2222-3333-444444444444" FechaFolioFiscalVinculado="2025-01-20" Mes="01"/>
+			</ieeh:IngresosHidrocarburos>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseIngresosHidrocarburos10().TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.IngresosHidrocarburos10, 1)
+
+	ingresos := data.IngresosHidrocarburos10[0]
+	assert.Equal(t, "CNH-R01-L01-A1/2015", ingresos.NumeroContrato)
+	assert.Equal(t, "150000.00", ingresos.ContraprestacionPagadaOperador)
+	assert.Equal(t, "35.000", ingresos.Porcentaje)
+	require.Len(t, ingresos.DocumentosRelacionados, 2)
+	assert.Equal(t, models.DocumentoRelacionadoIEEH{
+		FolioFiscalVinculado:      "AAAAAAAA-1111-2222-3333-444444444444",
+		FechaFolioFiscalVinculado: "2025-01-05",
+		Mes:                       "01",
+	}, ingresos.DocumentosRelacionados[0])
+}
+
+func TestGastosHidrocarburos10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:gceh="http://www.sat.gob.mx/GastosHidrocarburos10" Version="4.0">
+		<cfdi:Complemento>
+			<gceh:GastosHidrocarburos Version="1.0" NumeroContrato="CNH-R01-L01-A1/2015">
+				<gceh:Erogacion TipoErogacion="Costo" MontocuErogacion="50000.00" Porcentaje="100.000">
+					<gceh:DocumentoRelacionado OrigenErogacion="Nacional" FolioFiscalVinculado="cccccccc-1111-2222-3333-444444444444" RFCProveedor="PRO010101AB1" MontoTotalIVA="8000.00" FechaFolioFiscalVinculado="2025-01-10" Mes="01" MontoTotalErogaciones="58000.00"/>
+					<gceh:Actividades ActividadRelacionada="A1">
+						<gceh:SubActividades SubActividadRelacionada="S1">
+							<gceh:Tareas TareaRelacionada="T1"/>
+							<gceh:Tareas TareaRelacionada="T2"/>
+						</gceh:SubActividades>
+					</gceh:Actividades>
+					<gceh:CentroCostos Campo="CAMPO-1">
+						<gceh:Yacimientos Yacimiento="YAC-1">
+							<gceh:Pozos Pozo="POZO-1"/>
+							<gceh:Pozos Pozo="POZO-2"/>
+						</gceh:Yacimientos>
+					</gceh:CentroCostos>
+				</gceh:Erogacion>
+				<gceh:Erogacion TipoErogacion="Gasto" MontocuErogacion="1000.00" Porcentaje="100.000"/>
+			</gceh:GastosHidrocarburos>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	cfg := sax.NewDefaultConfig()
+	cfg.SafeNumerics = true
+	data, err := sax.NewCFDI40Handler(cfg).UseGastosHidrocarburos10().TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.GastosHidrocarburos10, 1)
+
+	gastos := data.GastosHidrocarburos10[0]
+	assert.Equal(t, "CNH-R01-L01-A1/2015", gastos.NumeroContrato)
+	require.Len(t, gastos.Erogaciones, 2)
+
+	erogacion := gastos.Erogaciones[0]
+	assert.Equal(t, "Costo", erogacion.TipoErogacion)
+	require.Len(t, erogacion.DocumentosRelacionados, 1)
+	docto := erogacion.DocumentosRelacionados[0]
+	assert.Equal(t, "CCCCCCCC-1111-2222-3333-444444444444", docto.FolioFiscalVinculado)
+	assert.Equal(t, "8000.00", docto.MontoTotalIVA)
+	assert.Equal(t, "0.00", docto.MontoRetencionISR)
+	assert.Equal(t, "58000.00", docto.MontoTotalErogaciones)
+
+	assert.Equal(t, []models.ActividadGCEH{{
+		ActividadRelacionada: "A1",
+		SubActividades:       []models.SubActividadGCEH{{SubActividadRelacionada: "S1", Tareas: []string{"T1", "T2"}}},
+	}}, erogacion.Actividades)
+	assert.Equal(t, []models.CentroCostosGCEH{{
+		Campo:       "CAMPO-1",
+		Yacimientos: []models.YacimientoGCEH{{Yacimiento: "YAC-1", Pozos: []string{"POZO-1", "POZO-2"}}},
+	}}, erogacion.CentroCostos)
+
+	assert.Equal(t, "Gasto", gastos.Erogaciones[1].TipoErogacion)
+	assert.Empty(t, gastos.Erogaciones[1].DocumentosRelacionados)
+}
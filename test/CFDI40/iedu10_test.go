@@ -0,0 +1,48 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestIEDU10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:iedu="http://www.sat.gob.mx/iedu" Version="4.0">
+		<cfdi:Conceptos>
+			<cfdi:Concepto ClaveProdServ="86121500" Cantidad="1" ClaveUnidad="E48" Descripcion="COLEGIATURA ENERO" ValorUnitario="3500.00" Importe="3500.00" ObjetoImp="01">
+				<cfdi:ComplementoConcepto>
+					<iedu:instEducativas version="1.0" nombreAlumno="JUAN PEREZ LOPEZ" CURP="pelj100101hdfrpn09" nivelEducativo="Primaria" autRVOE="SEP-1234" rfcPago="PEGA800101AB1"/>
+				</cfdi:ComplementoConcepto>
+			</cfdi:Concepto>
+			<cfdi:Concepto ClaveProdServ="86121500" Cantidad="1" ClaveUnidad="E48" Descripcion="INSCRIPCION" ValorUnitario="500.00" Importe="500.00" ObjetoImp="01"/>
+		</cfdi:Conceptos>
+	</cfdi:Comprobante>
+	`
+
+	t.Run("Parse instEducativas por concepto", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseConcepts().UseIEDU10().TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.CFDI40.Conceptos, 2)
+
+		assert.Equal(t, &models.IEDU10Data{
+			Version:        "1.0",
+			NombreAlumno:   "JUAN PEREZ LOPEZ",
+			CURP:           "PELJ100101HDFRPN09",
+			NivelEducativo: "Primaria",
+			AutRVOE:        "SEP-1234",
+			RfcPago:        "PEGA800101AB1",
+		}, data.CFDI40.Conceptos[0].InstEducativas)
+		assert.Nil(t, data.CFDI40.Conceptos[1].InstEducativas)
+	})
+
+	t.Run("Deshabilitado por defecto", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseConcepts().TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.CFDI40.Conceptos, 2)
+		assert.Nil(t, data.CFDI40.Conceptos[0].InstEducativas)
+	})
+}
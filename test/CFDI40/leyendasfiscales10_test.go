@@ -0,0 +1,42 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestLeyendasFiscales10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:leyendasFisc="http://www.sat.gob.mx/leyendasFiscales" Version="4.0">
+		<cfdi:Complemento>
+			<leyendasFisc:LeyendasFiscales version="1.0">
+				<leyendasFisc:Leyenda disposicionFiscal="RESDERAUTH" norma="Artículo 2011" textoLeyenda="Obra protegida"/>
+				<leyendasFisc:Leyenda textoLeyenda="Segunda leyenda"/>
+			</leyendasFisc:LeyendasFiscales>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	t.Run("Parse LeyendasFiscales10", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLeyendasFiscales10().TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.LeyendasFiscales10, 1)
+
+		leyendas := data.LeyendasFiscales10[0]
+		assert.Equal(t, "1.0", leyendas.Version)
+		assert.Equal(t, []models.Leyenda{
+			{DisposicionFiscal: "RESDERAUTH", Norma: "Artículo 2011", TextoLeyenda: "Obra protegida"},
+			{TextoLeyenda: "Segunda leyenda"},
+		}, leyendas.Leyendas)
+	})
+
+	t.Run("Deshabilitado por defecto", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(xmlStr)
+		require.NoError(t, err)
+		assert.Empty(t, data.LeyendasFiscales10)
+	})
+}
@@ -0,0 +1,129 @@
+package cfdi40_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const foreignElementsXML = `<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:ext="urn:example:extension" Version="4.0" Fecha="2025-01-15T10:30:00" SubTotal="1000.00" Moneda="MXN" Total="1160.00" TipoDeComprobante="I" Exportacion="01" LugarExpedicion="01000">
+	<cfdi:Emisor Rfc="AAA010101AAA" Nombre="EMISOR DE PRUEBA SA DE CV" RegimenFiscal="601"/>
+	<ext:Emisor Rfc="ZZZ010101ZZZ" Nombre="OTRO EMISOR"/>
+	<cfdi:Receptor Rfc="XAXX010101000" Nombre="PUBLICO EN GENERAL" DomicilioFiscalReceptor="01000" RegimenFiscalReceptor="616" UsoCFDI="G03"/>
+	<ext:Datos>
+		<ext:Receptor Rfc="YYY010101YYY"/>
+	</ext:Datos>
+	<cfdi:Impuestos TotalImpuestosTrasladados="160.00">
+		<cfdi:Traslados>
+			<cfdi:Traslado Base="1000.00" Impuesto="002" TipoFactor="Tasa" TasaOCuota="0.160000" Importe="160.00"/>
+		</cfdi:Traslados>
+	</cfdi:Impuestos>
+	<ext:Impuestos TotalImpuestosTrasladados="999.00"/>
+</cfdi:Comprobante>`
+
+// Elementos del CFDI sin namespace anidados donde el Comprobante no los espera.
+const nestedUnqualifiedXML = `<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" Version="4.0" Fecha="2025-01-15T10:30:00" SubTotal="1000.00" Moneda="MXN" Total="1160.00" TipoDeComprobante="I" Exportacion="01" LugarExpedicion="01000">
+	<cfdi:Emisor Rfc="AAA010101AAA" Nombre="EMISOR DE PRUEBA SA DE CV" RegimenFiscal="601"/>
+	<cfdi:Receptor Rfc="XAXX010101000" Nombre="PUBLICO EN GENERAL" DomicilioFiscalReceptor="01000" RegimenFiscalReceptor="616" UsoCFDI="G03"/>
+	<cfdi:Impuestos TotalImpuestosTrasladados="160.00"/>
+	<Otro>
+		<Impuestos TotalImpuestosTrasladados="999.00"/>
+	</Otro>
+	<Addenda>
+		<Pedido>
+			<Emisor Rfc="ZZZ010101ZZZ"/>
+			<Impuestos TotalImpuestosTrasladados="888.00"/>
+		</Pedido>
+	</Addenda>
+</cfdi:Comprobante>`
+
+// Salida de un PAC que declara los elementos del CFDI sin namespace.
+const unqualifiedXML = `<Comprobante Version="4.0" Fecha="2025-01-15T10:30:00" SubTotal="1000.00" Moneda="MXN" Total="1000.00" TipoDeComprobante="I" Exportacion="01" LugarExpedicion="01000">
+	<Emisor Rfc="AAA010101AAA" Nombre="EMISOR DE PRUEBA SA DE CV" RegimenFiscal="601"/>
+	<Receptor Rfc="XAXX010101000" Nombre="PUBLICO EN GENERAL" DomicilioFiscalReceptor="01000" RegimenFiscalReceptor="616" UsoCFDI="G03"/>
+</Comprobante>`
+
+func TestCFDI40HandlerNamespaces(t *testing.T) {
+	t.Run("Los elementos de otro namespace no sobrescriben el Comprobante", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(foreignElementsXML)
+		require.NoError(t, err)
+
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "EMISOR DE PRUEBA SA DE CV", data.CFDI40.Emisor.Nombre)
+		assert.Equal(t, "XAXX010101000", data.CFDI40.Receptor.RFC)
+		assert.Equal(t, "160.00", data.CFDI40.Impuestos.TotalImpuestosTrasladados)
+		assert.Len(t, data.CFDI40.Impuestos.Traslados, 1)
+		assert.Empty(t, data.Warnings)
+	})
+
+	t.Run("Un Comprobante de otro namespace es un error", func(t *testing.T) {
+		_, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(unqualifiedXML)
+		assert.ErrorContains(t, err, "http://www.sat.gob.mx/cfd/4")
+
+		cfdi33 := strings.Replace(foreignElementsXML, "http://www.sat.gob.mx/cfd/4", "http://www.sat.gob.mx/cfd/3", 1)
+		cfdi33 = strings.Replace(cfdi33, `Version="4.0"`, `Version="3.3"`, 1)
+		_, err = sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(cfdi33)
+		assert.EqualError(t, err, "incorrect type of CFDI, this handler only supports CFDI version 4.0")
+	})
+
+	t.Run("El modo tolerante lee los elementos sin namespace con advertencias", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLenientNamespaces().TransformFromString(unqualifiedXML)
+		require.NoError(t, err)
+
+		assert.Equal(t, "4.0", data.CFDI40.Version)
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "XAXX010101000", data.CFDI40.Receptor.RFC)
+		assert.Equal(t, []string{
+			"namespace mismatch: {}Comprobante read as {http://www.sat.gob.mx/cfd/4}Comprobante",
+			"namespace mismatch: {}Emisor read as {http://www.sat.gob.mx/cfd/4}Emisor",
+			"namespace mismatch: {}Receptor read as {http://www.sat.gob.mx/cfd/4}Receptor",
+		}, data.Warnings)
+	})
+
+	t.Run("El modo tolerante no sobrescribe un elemento del CFDI ya leido", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLenientNamespaces().TransformFromString(foreignElementsXML)
+		require.NoError(t, err)
+
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "XAXX010101000", data.CFDI40.Receptor.RFC)
+		assert.Equal(t, "160.00", data.CFDI40.Impuestos.TotalImpuestosTrasladados)
+		assert.Equal(t, []string{
+			"namespace mismatch: {urn:example:extension}Emisor ignored, {http://www.sat.gob.mx/cfd/4}Emisor already read",
+			"namespace mismatch: {urn:example:extension}Impuestos ignored, {http://www.sat.gob.mx/cfd/4}Impuestos already read",
+		}, data.Warnings)
+
+		bad := strings.Replace(unqualifiedXML, "<Comprobante ", `<Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:x="urn:example:x" `, 1)
+		bad = strings.Replace(bad, "<Emisor ", "<cfdi:Emisor ", 1)
+		bad = strings.Replace(bad, "<Receptor ", `<x:Emisor Rfc="BAD"/><Receptor `, 1)
+		data, err = sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLenientNamespaces().TransformFromString(bad)
+		require.NoError(t, err)
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Contains(t, data.Warnings, "namespace mismatch: {urn:example:x}Emisor ignored, {http://www.sat.gob.mx/cfd/4}Emisor already read")
+	})
+
+	t.Run("El elemento del CFDI leido despues reemplaza al de otro namespace", func(t *testing.T) {
+		xmlStr := strings.Replace(foreignElementsXML, `<cfdi:Emisor Rfc="AAA010101AAA" Nombre="EMISOR DE PRUEBA SA DE CV" RegimenFiscal="601"/>
+	<ext:Emisor Rfc="ZZZ010101ZZZ" Nombre="OTRO EMISOR"/>`, `<ext:Emisor Rfc="ZZZ010101ZZZ" Nombre="OTRO EMISOR"/>
+	<cfdi:Emisor Rfc="AAA010101AAA" Nombre="EMISOR DE PRUEBA SA DE CV" RegimenFiscal="601"/>`, 1)
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLenientNamespaces().TransformFromString(xmlStr)
+		require.NoError(t, err)
+
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "EMISOR DE PRUEBA SA DE CV", data.CFDI40.Emisor.Nombre)
+		assert.Contains(t, data.Warnings, "namespace mismatch: {urn:example:extension}Emisor read as {http://www.sat.gob.mx/cfd/4}Emisor")
+	})
+
+	t.Run("El modo tolerante ignora los elementos fuera de su profundidad", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseLenientNamespaces().TransformFromString(nestedUnqualifiedXML)
+		require.NoError(t, err)
+
+		assert.Equal(t, "AAA010101AAA", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "160.00", data.CFDI40.Impuestos.TotalImpuestosTrasladados)
+		assert.Equal(t, []string{
+			"namespace mismatch: {}Addenda read as {http://www.sat.gob.mx/cfd/4}Addenda",
+		}, data.Warnings)
+	})
+}
@@ -0,0 +1,75 @@
+package cfdi40_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestNomina12Multiple(t *testing.T) {
+	t.Run("Nominas en el orden del documento", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseNomina12().TransformFromFile("../recursos/nomina12_multiple.xml")
+		require.NoError(t, err)
+
+		require.Len(t, data.Nomina12, 2)
+		extraordinaria, ordinaria := data.Nomina12[0], data.Nomina12[1]
+
+		assert.Equal(t, "E", extraordinaria.TipoNomina)
+		assert.Equal(t, "99", extraordinaria.Receptor.PeriodicidadPago)
+		assert.Equal(t, "XOJI740919MJCDMN05", extraordinaria.Receptor.Curp)
+		require.Len(t, extraordinaria.Percepciones.Percepcion, 1)
+		assert.Equal(t, "Aguinaldo", extraordinaria.Percepciones.Percepcion[0].Concepto)
+		require.Len(t, extraordinaria.Deducciones.Deduccion, 1)
+		assert.Equal(t, "300.00", extraordinaria.Deducciones.Deduccion[0].Importe)
+
+		assert.Equal(t, "O", ordinaria.TipoNomina)
+		assert.Equal(t, "04", ordinaria.Receptor.PeriodicidadPago)
+		assert.Equal(t, "16", ordinaria.NumDiasPagados)
+		require.Len(t, ordinaria.Deducciones.Deduccion, 2)
+		assert.Equal(t, "950.00", ordinaria.Deducciones.Deduccion[1].Importe)
+
+		// Los Emisor y Receptor de la nomina y de las extensiones no alteran los del Comprobante
+		assert.Equal(t, "EKU9003173C9", data.CFDI40.Emisor.RFC)
+		assert.Equal(t, "XOJI740919U48", data.CFDI40.Receptor.RFC)
+		assert.Equal(t, "B5510768108", extraordinaria.Emisor.RegistroPatronal)
+
+		require.Len(t, data.TFD11, 1)
+		assert.Equal(t, "22222222-2222-2222-2222-222222222222", data.TFD11[0].UUID)
+
+		assert.Equal(t, []models.ComplementRef{
+			{Namespace: "http://www.sat.gob.mx/nomina12", Local: "Nomina", Version: "1.2"},
+			{Namespace: "http://www.sat.gob.mx/TimbreFiscalDigital", Local: "TimbreFiscalDigital", Version: "1.1"},
+			{Namespace: "http://www.sat.gob.mx/nomina12", Local: "Nomina", Version: "1.2"},
+		}, data.CFDI40.ComplementosRefs)
+	})
+
+	t.Run("Nominas sin parsear como complementos crudos", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseRawComplements().TransformFromFile("../recursos/nomina12_multiple.xml")
+		require.NoError(t, err)
+
+		assert.Empty(t, data.Nomina12)
+		require.Len(t, data.RawComplementos, 2)
+		assert.Contains(t, string(data.RawComplementos[0].Raw), `TipoNomina="E"`)
+		assert.Contains(t, string(data.RawComplementos[1].Raw), `TipoNomina="O"`)
+		require.Len(t, data.TFD11, 1)
+	})
+}
+
+func TestImpuestosWithForeignElements(t *testing.T) {
+	content, err := os.ReadFile("../recursos/cfdi40.xml")
+	require.NoError(t, err)
+	xmlStr := strings.Replace(string(content), `<cfdi:Impuestos TotalImpuestosTrasladados="160.00">`,
+		`<cfdi:Impuestos TotalImpuestosTrasladados="160.00"><ext:Impuestos xmlns:ext="urn:example:extension"><ext:Traslado Importe="1.00"/></ext:Impuestos>`, 1)
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(xmlStr)
+	require.NoError(t, err)
+
+	require.Len(t, data.CFDI40.Impuestos.Traslados, 1)
+	assert.Equal(t, "160.00", data.CFDI40.Impuestos.Traslados[0].Importe)
+	require.Len(t, data.TFD11, 1)
+}
@@ -0,0 +1,49 @@
+package cfdi40_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestNomina12SubcontratacionYEntidadSNCF(t *testing.T) {
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseNomina12()
+	data, err := handler.TransformFromFile("../recursos/nomina12.xml")
+	require.NoError(t, err)
+	require.Len(t, data.Nomina12, 1)
+	nomina := data.Nomina12[0]
+
+	require.Len(t, nomina.Receptor.Subcontrataciones, 2)
+	assert.Equal(t, "AAA010101AAA", nomina.Receptor.Subcontrataciones[0].RfcLabora)
+	assert.Equal(t, "23.45", nomina.Receptor.Subcontrataciones[0].PorcentajeTiempo)
+	assert.Equal(t, "BBB010101AAA", nomina.Receptor.Subcontrataciones[1].RfcLabora)
+	assert.Equal(t, "13.45", nomina.Receptor.Subcontrataciones[1].PorcentajeTiempo)
+
+	assert.Equal(t, "IP", nomina.Emisor.EntidadSNCF.OrigenRecurso)
+	assert.Equal(t, "123.45", nomina.Emisor.EntidadSNCF.MontoRecursoPropio)
+	// Los nodos hijos del Receptor no alteran los datos del Receptor
+	assert.Equal(t, "001", nomina.Receptor.NumEmpleado)
+}
+
+func TestNomina12SinSubcontratacionNiEntidadSNCF(t *testing.T) {
+	content, err := os.ReadFile("../recursos/nomina12.xml")
+	require.NoError(t, err)
+	xmlStr := string(content)
+	xmlStr = strings.Replace(xmlStr, `<nomina12:EntidadSNCF OrigenRecurso="IP" MontoRecursoPropio="123.45" />`, "", 1)
+	xmlStr = strings.Replace(xmlStr, `<nomina12:SubContratacion RfcLabora="AAA010101AAA" PorcentajeTiempo="23.45" />`, "", 1)
+	xmlStr = strings.Replace(xmlStr, `<nomina12:SubContratacion RfcLabora="BBB010101AAA" PorcentajeTiempo="13.45" />`, "", 1)
+
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseNomina12()
+	data, err := handler.TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.Nomina12, 1)
+	nomina := data.Nomina12[0]
+
+	assert.Empty(t, nomina.Receptor.Subcontrataciones)
+	assert.Empty(t, nomina.Emisor.EntidadSNCF.OrigenRecurso)
+	assert.Equal(t, "E23-12345-12-1", nomina.Emisor.RegistroPatronal)
+}
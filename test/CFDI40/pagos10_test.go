@@ -0,0 +1,103 @@
+package cfdi40_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestPagos10Handler(t *testing.T) {
+	content, err := os.ReadFile("../recursos/cfdi33_pagos10.xml")
+	require.NoError(t, err)
+
+	cfg := sax.NewDefaultConfig()
+	cfg.SafeNumerics = true
+	handler := sax.NewPagos10Handler(cfg)
+
+	data, err := handler.TransformFromBytes(content)
+	require.NoError(t, err)
+
+	t.Run("Parse Pagos10 desde un CFDI 3.3", func(t *testing.T) {
+		assert.Equal(t, "1.0", data.Version)
+		require.Len(t, data.Pagos, 2)
+
+		pago := data.Pagos[0]
+		assert.Equal(t, "USD", pago.MonedaP)
+		assert.Equal(t, "20.00", pago.TipoCambioP)
+		require.Len(t, pago.DoctoRelacionado, 1)
+		assert.Equal(t, models.DoctoRelacionado10{
+			IdDocumento:      "22222222-2222-2222-2222-222222222222",
+			Serie:            "F",
+			Folio:            "10",
+			MonedaDR:         "MXN",
+			TipoCambioDR:     "20.00",
+			MetodoDePagoDR:   "PPD",
+			NumParcialidad:   "1",
+			ImpSaldoAnt:      "3480.00",
+			ImpPagado:        "2320.00",
+			ImpSaldoInsoluto: "1160.00",
+		}, pago.DoctoRelacionado[0])
+		require.Len(t, pago.Impuestos, 1)
+		assert.Equal(t, []models.Retencion10{{Impuesto: "001", Importe: "1.00"}}, pago.Impuestos[0].Retenciones)
+		assert.Equal(t, []models.Traslado10{{Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.160000", Importe: "16.00"}}, pago.Impuestos[0].Traslados)
+
+		assert.Equal(t, "1.00", data.Pagos[1].TipoCambioP)
+		assert.Equal(t, "1.00", data.Pagos[1].DoctoRelacionado[0].TipoCambioDR)
+	})
+
+	t.Run("ToPagos20 normaliza al formato de Pagos 2.0", func(t *testing.T) {
+		pagos20 := handler.ToPagos20(data)
+		assert.Equal(t, "1.0", pagos20.Version)
+		require.Len(t, pagos20.Pagos, 2)
+
+		docto := pagos20.Pagos[0].DoctoRelacionado[0]
+		assert.Equal(t, "20.00", docto.EquivalenciaDR)
+		assert.Equal(t, "2320.00", docto.ImpPagado)
+		require.Len(t, pagos20.Pagos[0].ImpuestosP, 1)
+		assert.Equal(t, "0.160000", pagos20.Pagos[0].ImpuestosP[0].TrasladosP[0].TasaOCuotaP)
+
+		// 116.00 USD * 20.00 + 1160.00 MXN
+		assert.Equal(t, "3480.00", pagos20.Totales.MontoTotalPagos)
+		assert.Equal(t, "320.00", pagos20.Totales.TotalTrasladosImpuestoIVA16)
+		assert.Equal(t, "20.00", pagos20.Totales.TotalRetencionesISR)
+		assert.Equal(t, "0.00", pagos20.Totales.TotalRetencionesIVA)
+		// Pagos 1.0 no tiene bases: no se reportan como cero
+		assert.Equal(t, cfg.EmptyChar, pagos20.Totales.TotalTrasladosBaseIVA16)
+		assert.Equal(t, cfg.EmptyChar, pagos20.Totales.TotalTrasladosBaseIVAExento)
+		assert.Equal(t, cfg.EmptyChar, pagos20.Pagos[0].ImpuestosP[0].TrasladosP[0].BaseP)
+	})
+
+	t.Run("El resumen de impuestos deja vacia la base que Pagos 1.0 no tiene", func(t *testing.T) {
+		pagos20 := handler.ToPagos20(data)
+		require.Len(t, pagos20.Pagos, 2)
+
+		assert.Equal(t, []models.ResumenImpuestoP{
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.160000", Importe: "320.00"},
+			{Tipo: sax.ResumenRetencion, Impuesto: "001", Importe: "20.00"},
+		}, pagos20.ResumenImpuestos)
+		for _, pago := range pagos20.Pagos {
+			for _, item := range pago.ResumenImpuestosP {
+				assert.Empty(t, item.Base)
+			}
+		}
+		assert.NotEmpty(t, pagos20.Pagos[0].ResumenImpuestosP)
+	})
+
+	t.Run("Los totales se redondean a la mitad alejandose de cero", func(t *testing.T) {
+		conTipoCambio := *data
+		conTipoCambio.Pagos = append([]models.Pago10(nil), data.Pagos...)
+		conTipoCambio.Pagos[0].TipoCambioP = "20.00125"
+
+		// 116.00 USD * 20.00125 + 1160.00 MXN = 3480.145
+		assert.Equal(t, "3480.15", handler.ToPagos20(&conTipoCambio).Totales.MontoTotalPagos)
+	})
+
+	t.Run("Rechaza Pagos 2.0", func(t *testing.T) {
+		_, err := handler.TransformFromString(`<pago20:Pagos xmlns:pago20="http://www.sat.gob.mx/Pagos20" Version="2.0"/>`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestPagos20ResumenImpuestos(t *testing.T) {
+	xmlStr := `
+<cfdi:Comprobante Version="4.0" Fecha="2025-03-01T12:00:00" SubTotal="0" Moneda="XXX" Total="0" TipoDeComprobante="P" xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:pago20="http://www.sat.gob.mx/Pagos20">
+    <cfdi:Complemento>
+        <pago20:Pagos Version="2.0">
+            <pago20:Totales MontoTotalPagos="3480.00"/>
+            <pago20:Pago FechaPago="2025-03-01T12:00:00" FormaDePagoP="03" MonedaP="USD" TipoCambioP="20.00" Monto="116.00">
+                <pago20:ImpuestosP>
+                    <pago20:RetencionesP>
+                        <pago20:RetencionP ImpuestoP="002" ImporteP="10.67"/>
+                    </pago20:RetencionesP>
+                    <pago20:TrasladosP>
+                        <pago20:TrasladoP BaseP="100.00" ImpuestoP="002" TipoFactorP="Tasa" TasaOCuotaP="0.160000" ImporteP="16.00"/>
+                    </pago20:TrasladosP>
+                </pago20:ImpuestosP>
+            </pago20:Pago>
+            <pago20:Pago FechaPago="2025-03-02T12:00:00" FormaDePagoP="03" MonedaP="MXN" TipoCambioP="1" Monto="1160.00">
+                <pago20:ImpuestosP>
+                    <pago20:TrasladosP>
+                        <pago20:TrasladoP BaseP="500.00" ImpuestoP="002" TipoFactorP="Tasa" TasaOCuotaP="0.16" ImporteP="80.00"/>
+                        <pago20:TrasladoP BaseP="500.00" ImpuestoP="002" TipoFactorP="Tasa" TasaOCuotaP="0.000000" ImporteP="0.00"/>
+                        <pago20:TrasladoP BaseP="100.00" ImpuestoP="002" TipoFactorP="Exento"/>
+                    </pago20:TrasladosP>
+                </pago20:ImpuestosP>
+            </pago20:Pago>
+        </pago20:Pagos>
+    </cfdi:Complemento>
+</cfdi:Comprobante>
+`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UsePagos20().TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.Pagos20, 1)
+	pagos := data.Pagos20[0]
+	require.Len(t, pagos.Pagos, 2)
+
+	t.Run("Resumen por pago en MXN", func(t *testing.T) {
+		assert.Equal(t, []models.ResumenImpuestoP{
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.160000", Base: "2000.00", Importe: "320.00"},
+			{Tipo: sax.ResumenRetencion, Impuesto: "002", Importe: "213.40"},
+		}, pagos.Pagos[0].ResumenImpuestosP)
+	})
+
+	t.Run("Agregado del documento", func(t *testing.T) {
+		assert.Equal(t, []models.ResumenImpuestoP{
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.160000", Base: "2500.00", Importe: "400.00"},
+			{Tipo: sax.ResumenRetencion, Impuesto: "002", Importe: "213.40"},
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.000000", Base: "500.00", Importe: "0.00"},
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Exento", TasaOCuota: "", Base: "100.00", Importe: "0.00"},
+		}, pagos.ResumenImpuestos)
+	})
+
+	t.Run("Se redondea en MXN a la mitad alejandose de cero", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UsePagos20().TransformFromString(`
+<cfdi:Comprobante Version="4.0" xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:pago20="http://www.sat.gob.mx/Pagos20">
+    <cfdi:Complemento>
+        <pago20:Pagos Version="2.0">
+            <pago20:Pago FechaPago="2025-03-01T12:00:00" FormaDePagoP="03" MonedaP="JPY" TipoCambioP="0.13125" Monto="1100">
+                <pago20:ImpuestosP>
+                    <pago20:TrasladosP>
+                        <pago20:TrasladoP BaseP="1000" ImpuestoP="002" TipoFactorP="Tasa" TasaOCuotaP="0.100000" ImporteP="100"/>
+                    </pago20:TrasladosP>
+                </pago20:ImpuestosP>
+            </pago20:Pago>
+        </pago20:Pagos>
+    </cfdi:Complemento>
+</cfdi:Comprobante>`)
+		require.NoError(t, err)
+		require.Len(t, data.Pagos20, 1)
+
+		// 1000 JPY * 0.13125 = 131.25 MXN y 100 JPY * 0.13125 = 13.125 MXN
+		assert.Equal(t, []models.ResumenImpuestoP{
+			{Tipo: sax.ResumenTraslado, Impuesto: "002", TipoFactor: "Tasa", TasaOCuota: "0.100000", Base: "131.25", Importe: "13.13"},
+		}, data.Pagos20[0].ResumenImpuestos)
+	})
+
+	t.Run("Sin impuestos no hay resumen", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UsePagos20().TransformFromString(`
+<cfdi:Comprobante Version="4.0" xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:pago20="http://www.sat.gob.mx/Pagos20">
+    <cfdi:Complemento>
+        <pago20:Pagos Version="2.0">
+            <pago20:Pago FechaPago="2025-03-01T12:00:00" FormaDePagoP="03" MonedaP="MXN" Monto="100.00"/>
+        </pago20:Pagos>
+    </cfdi:Complemento>
+</cfdi:Comprobante>`)
+		require.NoError(t, err)
+		require.Len(t, data.Pagos20, 1)
+		assert.Empty(t, data.Pagos20[0].ResumenImpuestos)
+	})
+}
@@ -0,0 +1,75 @@
+package cfdi40_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const unknownComplementsXML = `<leyendasFisc:LeyendasFiscales xmlns:leyendasFisc="http://www.sat.gob.mx/leyendasFiscales" version="1.0">
+            <leyendasFisc:Leyenda disposicionFiscal="RESDERAUTH" norma="Artículo 2011" textoLeyenda="Leyenda de prueba"/>
+        </leyendasFisc:LeyendasFiscales>
+        <donat:Donatarias xmlns:donat="http://www.sat.gob.mx/donat" version="1.1" noAutorizacion="123" fechaAutorizacion="2024-01-01" leyenda="Donativo"/>
+    </cfdi:Complemento>`
+
+func cfdiWithUnknownComplements(t *testing.T) string {
+	t.Helper()
+	content, err := os.ReadFile("../recursos/cfdi40.xml")
+	require.NoError(t, err)
+	return strings.Replace(string(content), "</cfdi:Complemento>", unknownComplementsXML, 1)
+}
+
+func TestCFDI40RawComplements(t *testing.T) {
+	xmlStr := cfdiWithUnknownComplements(t)
+
+	t.Run("Deshabilitado por defecto", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromString(xmlStr)
+		require.NoError(t, err)
+		assert.Empty(t, data.RawComplementos)
+		require.Len(t, data.TFD11, 1)
+	})
+
+	t.Run("Captura los complementos no soportados", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseRawComplements().TransformFromString(xmlStr)
+		require.NoError(t, err)
+		require.Len(t, data.TFD11, 1)
+		require.Len(t, data.RawComplementos, 2)
+
+		leyendas := data.RawComplementos[0]
+		assert.Equal(t, "http://www.sat.gob.mx/leyendasFiscales", leyendas.Namespace)
+		assert.Equal(t, "LeyendasFiscales", leyendas.Local)
+		assert.Equal(t, "1.0", leyendas.Version)
+		assert.True(t, strings.HasPrefix(string(leyendas.Raw), "<leyendasFisc:LeyendasFiscales "))
+		assert.True(t, strings.HasSuffix(string(leyendas.Raw), "</leyendasFisc:LeyendasFiscales>"))
+		assert.Contains(t, string(leyendas.Raw), `textoLeyenda="Leyenda de prueba"`)
+
+		donat := data.RawComplementos[1]
+		assert.Equal(t, "Donatarias", donat.Local)
+		assert.Equal(t, "1.1", donat.Version)
+		assert.True(t, strings.HasSuffix(string(donat.Raw), `leyenda="Donativo"/>`))
+	})
+
+	t.Run("Los complementos soportados deshabilitados se capturan", func(t *testing.T) {
+		data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseRawComplements().TransformFromFile("../recursos/cfdi40_pagos.xml")
+		require.NoError(t, err)
+		require.Len(t, data.RawComplementos, 1)
+		assert.Equal(t, "Pagos", data.RawComplementos[0].Local)
+		assert.Equal(t, "2.0", data.RawComplementos[0].Version)
+		assert.True(t, strings.HasPrefix(string(data.RawComplementos[0].Raw), `<pago20:Pagos xmlns:pago20="http://www.sat.gob.mx/Pagos20" `))
+
+		// El namespace se declara en cfdi:Comprobante y se vuelve a declarar en el complemento crudo
+		pagos, err := sax.NewPagos20Handler(sax.NewDefaultConfig()).TransformFromBytes(data.RawComplementos[0].Raw)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0", pagos.Version)
+		assert.Len(t, pagos.Pagos, 1)
+
+		data, err = sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseRawComplements().UsePagos20().TransformFromFile("../recursos/cfdi40_pagos.xml")
+		require.NoError(t, err)
+		assert.Empty(t, data.RawComplementos)
+		require.Len(t, data.Pagos20, 1)
+	})
+}
@@ -0,0 +1,38 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestServicioParcial10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:servicioparcial="http://www.sat.gob.mx/servicioparcialconstruccion" Version="4.0">
+		<cfdi:Complemento>
+			<servicioparcial:parcialesconstruccion Version="1.0" NumPerLicoAut="LIC-2025-001">
+				<servicioparcial:Inmueble Calle="AV. REFORMA" NoExterior="100" Colonia="JUAREZ" Municipio="015" Estado="09" CodigoPostal="06600"/>
+			</servicioparcial:parcialesconstruccion>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseServicioParcial10().TransformFromString(xmlStr)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.ServicioParcial10Data{{
+		Version:       "1.0",
+		NumPerLicoAut: "LIC-2025-001",
+		Inmueble: models.Inmueble{
+			Calle:        "AV. REFORMA",
+			NoExterior:   "100",
+			Colonia:      "JUAREZ",
+			Municipio:    "015",
+			Estado:       "09",
+			CodigoPostal: "06600",
+		},
+	}}, data.ServicioParcial10)
+}
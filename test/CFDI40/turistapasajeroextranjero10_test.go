@@ -0,0 +1,39 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestTuristaPasajeroExtranjero10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:tpe="http://www.sat.gob.mx/TuristaPasajeroExtranjero" Version="4.0">
+		<cfdi:Complemento>
+			<tpe:TuristaPasajeroExtranjero version="1.0" fechadeTransito="2025-02-01T08:00:00" tipoTransito="Arribo">
+				<tpe:datosTransito Via="Aérea" TipoId="Pasaporte" NumeroId="X1234567" Nacionalidad="USA" EmpresaTransporte="AEROLINEA SA" IdTransporte="AM123"/>
+			</tpe:TuristaPasajeroExtranjero>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseTuristaPasajeroExtranjero10().TransformFromString(xmlStr)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.TuristaPasajeroExtranjero10Data{{
+		Version:         "1.0",
+		FechadeTransito: "2025-02-01T08:00:00",
+		TipoTransito:    "Arribo",
+		DatosTransito: models.DatosTransito{
+			Via:               "Aérea",
+			TipoID:            "Pasaporte",
+			NumeroID:          "X1234567",
+			Nacionalidad:      "USA",
+			EmpresaTransporte: "AEROLINEA SA",
+			IDTransporte:      "AM123",
+		},
+	}}, data.TuristaPasajeroExtranjero10)
+}
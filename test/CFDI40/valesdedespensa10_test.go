@@ -0,0 +1,47 @@
+package cfdi40_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func TestValesDeDespensa10Handler(t *testing.T) {
+	xmlStr := `
+	<cfdi:Comprobante xmlns:cfdi="http://www.sat.gob.mx/cfd/4" xmlns:valesdedespensa="http://www.sat.gob.mx/valesdedespensa" Version="4.0">
+		<cfdi:Complemento>
+			<valesdedespensa:ValesDeDespensa version="1.0" tipoOperacion="monedero electrónico" numeroDeCuenta="0011223344" total="3000.00">
+				<valesdedespensa:Conceptos>
+					<valesdedespensa:Concepto identificador="T-1" fecha="2025-01-31T00:00:00" rfc="PEGJ800101AB1" curp="pegj800101hdfrpn09" nombre="JUAN PEREZ" numSeguridadSocial="12345678901" importe="1500.00"/>
+					<valesdedespensa:Concepto identificador="T-2" fecha="2025-01-31T00:00:00" rfc="LOMA850505CD2" curp="LOMA850505MDFPRN01" nombre="ANA LOPEZ" importe="1500.00"/>
+				</valesdedespensa:Conceptos>
+			</valesdedespensa:ValesDeDespensa>
+		</cfdi:Complemento>
+	</cfdi:Comprobante>
+	`
+
+	cfg := sax.NewDefaultConfig()
+	cfg.EmptyChar = "-"
+	data, err := sax.NewCFDI40Handler(cfg).UseValesDeDespensa10().TransformFromString(xmlStr)
+	require.NoError(t, err)
+	require.Len(t, data.ValesDeDespensa10, 1)
+
+	vales := data.ValesDeDespensa10[0]
+	assert.Equal(t, "monedero electrónico", vales.TipoOperacion)
+	assert.Equal(t, "-", vales.RegistroPatronal)
+	assert.Equal(t, "3000.00", vales.Total)
+	require.Len(t, vales.Conceptos, 2)
+	assert.Equal(t, models.ValesDeDespensaConcepto{
+		Identificador:      "T-1",
+		Fecha:              "2025-01-31T00:00:00",
+		Rfc:                "PEGJ800101AB1",
+		Curp:               "PEGJ800101HDFRPN09",
+		Nombre:             "JUAN PEREZ",
+		NumSeguridadSocial: "12345678901",
+		Importe:            "1500.00",
+	}, vales.Conceptos[0])
+	assert.Equal(t, "-", vales.Conceptos[1].NumSeguridadSocial)
+}
@@ -0,0 +1,101 @@
+package cfdi40_test
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/metadata"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const metadataTXT = "Uuid~RfcEmisor~NombreEmisor~RfcReceptor~NombreReceptor~RfcPac~FechaEmision~FechaCertificacionSat~Monto~EfectoComprobante~Estatus~FechaCancelacion\r\n" +
+	"11111111-1111-1111-1111-111111111111~ESO121212R82~EMPRESA DE SERVICIOS ONLINE~XAXX010101000~PUBLICO EN GENERAL~SAT970701NN3~2023-10-27 12:00:00~2023-10-27 12:05:00~0.00~P~0~2023-11-01 09:00:00\r\n" +
+	"a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c~AAA010101AAA~EMISOR DE PRUEBA SA DE CV~XAXX010101000~PUBLICO EN GENERAL~AAA010101AAA~2025-01-15 10:30:00~2025-01-15 10:30:01~1160.00~I~1~\r\n"
+
+func writeZip(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "paquete.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return path
+}
+
+func TestCFDI40Handler_TransformFromZip(t *testing.T) {
+	files := map[string][]byte{"metadata.txt": []byte(metadataTXT), "roto.xml": []byte("<cfdi:Comprobante")}
+	for _, name := range []string{"cfdi40.xml", "cfdi40_pagos.xml"} {
+		content, err := os.ReadFile(filepath.Join("../recursos", name))
+		require.NoError(t, err)
+		files[name] = content
+	}
+
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UsePagos20()
+	it, err := handler.TransformFromZip(writeZip(t, files))
+	require.NoError(t, err)
+	defer it.Close()
+
+	assert.Len(t, it.Metadata(), 2)
+
+	var entries []sax.ZipEntry
+	for it.Next() {
+		entries = append(entries, it.Entry())
+	}
+	require.Len(t, entries, 3)
+
+	// Los XML se recorren ordenados por nombre
+	assert.Equal(t, "cfdi40.xml", entries[0].Name)
+	require.NoError(t, entries[0].Err)
+	require.NotNil(t, entries[0].Metadata)
+	assert.False(t, entries[0].Metadata.Cancelado())
+	assert.Equal(t, "1160", entries[0].Metadata.Monto.String())
+
+	require.NoError(t, entries[1].Err)
+	assert.Len(t, entries[1].Data.Pagos20, 1)
+	require.NotNil(t, entries[1].Metadata)
+	assert.True(t, entries[1].Metadata.Cancelado())
+	assert.Equal(t, "2023-11-01 09:00:00", entries[1].Metadata.FechaCancelacion.Format("2006-01-02 15:04:05"))
+
+	assert.Equal(t, "roto.xml", entries[2].Name)
+	assert.Error(t, entries[2].Err)
+	assert.Nil(t, entries[2].Data)
+}
+
+func TestCFDI40Handler_TransformFromZipMalformedMetadata(t *testing.T) {
+	lines := strings.Split(metadataTXT, "\r\n")
+	malformed := strings.Join([]string{lines[0], lines[1], "22222222-2222-2222-2222-222222222222~ESO121212R82~MONTO INVALIDO", lines[2]}, "\r\n")
+
+	content, err := os.ReadFile("../recursos/cfdi40.xml")
+	require.NoError(t, err)
+	files := map[string][]byte{"metadata.txt": []byte(malformed), "cfdi40.xml": content}
+
+	it, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromZip(writeZip(t, files))
+	require.NoError(t, err)
+	defer it.Close()
+
+	assert.Len(t, it.Metadata(), 2)
+	require.Len(t, it.MetadataErrors(), 1)
+	var parseErr *metadata.ParseError
+	require.True(t, errors.As(it.MetadataErrors()[0], &parseErr))
+	assert.Equal(t, 3, parseErr.Line)
+	assert.Contains(t, it.MetadataErrors()[0].Error(), "metadata.txt")
+
+	require.True(t, it.Next())
+	require.NoError(t, it.Entry().Err)
+	assert.NotNil(t, it.Entry().Metadata)
+	assert.False(t, it.Next())
+}
@@ -0,0 +1,199 @@
+package batch_test
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/batch"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+var recursos = []string{"cfdi40.xml", "cfdi40_pagos.xml", "nomina12.xml"}
+
+// newBatchDir copies the test resources n times into a temp dir, plus one invalid file.
+func newBatchDir(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		for _, name := range recursos {
+			content, err := os.ReadFile(filepath.Join("../recursos", name))
+			require.NoError(t, err)
+			sub := filepath.Join(dir, string(rune('a'+i)))
+			require.NoError(t, os.MkdirAll(sub, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(sub, name), content, 0o644))
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "roto.xml"), []byte("<cfdi:Comprobante"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notas.txt"), []byte("no es xml"), 0o644))
+	return dir
+}
+
+func TestProcessDir(t *testing.T) {
+	dir := newBatchDir(t, 4)
+	sources, err := batch.DirSources(dir)
+	require.NoError(t, err)
+	require.Len(t, sources, 13)
+
+	t.Run("Ordered entrega los resultados en el orden de entrada", func(t *testing.T) {
+		var calls []batch.Progress
+		processor := batch.NewProcessor(sax.NewDefaultConfig(), batch.Options{
+			Workers:    4,
+			Ordered:    true,
+			OnProgress: func(p batch.Progress) { calls = append(calls, p) },
+		})
+		results, err := processor.ProcessDir(context.Background(), dir)
+		require.NoError(t, err)
+
+		i := 0
+		for res := range results {
+			assert.Equal(t, i, res.Index)
+			assert.Equal(t, sources[i].Path, res.Path)
+			i++
+		}
+		assert.Equal(t, 13, i)
+
+		require.Len(t, calls, 13)
+		last := calls[len(calls)-1]
+		assert.Equal(t, 13, last.Total)
+		assert.Equal(t, 13, last.Done)
+		assert.Equal(t, 1, last.Failed)
+	})
+
+	t.Run("Collect agrega los errores por archivo", func(t *testing.T) {
+		processor := batch.NewProcessor(sax.NewDefaultConfig(), batch.Options{Workers: 3})
+		docs, err := batch.Collect(processor.Process(context.Background(), sources))
+		assert.Len(t, docs, 12)
+
+		var errs batch.Errors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 1)
+		assert.Equal(t, filepath.Join(dir, "roto.xml"), errs[0].Path)
+	})
+
+}
+
+// blockingSource returns a Source whose Open signals opened and waits for release.
+func blockingSource(opened, release chan struct{}) batch.Source {
+	return batch.Source{
+		Path: "lento.xml",
+		Open: func() (io.ReadCloser, error) {
+			close(opened)
+			<-release
+			return io.NopCloser(strings.NewReader("<cfdi:Comprobante")), nil
+		},
+	}
+}
+
+func TestProcessCancel(t *testing.T) {
+	dir := newBatchDir(t, 4)
+	files, err := batch.DirSources(dir)
+	require.NoError(t, err)
+
+	opened, release := make(chan struct{}), make(chan struct{})
+	sources := append([]batch.Source{blockingSource(opened, release)}, files...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	processor := batch.NewProcessor(sax.NewDefaultConfig(), batch.Options{Workers: 1, Ordered: true})
+	results := processor.Process(ctx, sources)
+
+	<-opened
+	cancel()
+	close(release)
+
+	// Only the document being parsed when ctx was cancelled may still be delivered
+	received := 0
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				done = true
+				continue
+			}
+			received++
+		case <-timeout:
+			t.Fatal("results channel was not closed after cancelling the context")
+		}
+	}
+	assert.LessOrEqual(t, received, 1)
+}
+
+func TestProcessOrderedWindow(t *testing.T) {
+	dir := newBatchDir(t, 4)
+	files, err := batch.DirSources(dir)
+	require.NoError(t, err)
+
+	var opens atomic.Int32
+	counted := func(src batch.Source) batch.Source {
+		open := src.Open
+		src.Open = func() (io.ReadCloser, error) {
+			opens.Add(1)
+			return open()
+		}
+		return src
+	}
+
+	opened, release := make(chan struct{}), make(chan struct{})
+	sources := []batch.Source{counted(blockingSource(opened, release))}
+	for _, src := range files {
+		sources = append(sources, counted(src))
+	}
+
+	workers := 2
+	window := int32(workers * batch.ReorderWindow)
+	require.Greater(t, len(sources), int(window))
+
+	processor := batch.NewProcessor(sax.NewDefaultConfig(), batch.Options{Workers: workers, Ordered: true})
+	results := processor.Process(context.Background(), sources)
+
+	// While the first document blocks, no more than the window is dispatched
+	<-opened
+	require.Eventually(t, func() bool { return opens.Load() == window }, 5*time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, window, opens.Load())
+
+	close(release)
+	i := 0
+	for res := range results {
+		assert.Equal(t, i, res.Index)
+		i++
+	}
+	assert.Equal(t, len(sources), i)
+}
+
+func TestProcessZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paquete.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, name := range recursos {
+		content, err := os.ReadFile(filepath.Join("../recursos", name))
+		require.NoError(t, err)
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	processor := batch.NewProcessor(sax.NewDefaultConfig(), batch.Options{Workers: 2, Ordered: true})
+	results, err := processor.ProcessZip(context.Background(), path)
+	require.NoError(t, err)
+
+	docs, err := batch.Collect(results)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "1160.00", docs[0].CFDI40.Total)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", docs[1].TFD11[0].UUID)
+}
@@ -0,0 +1,178 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const recursos = "../recursos/"
+
+// Codigos de salida del comando.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// fileError es una entrada del reporte de errores por archivo.
+type fileError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// gocfdi es la ruta del binario compilado por TestMain.
+var gocfdi string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gocfdi")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	gocfdi = filepath.Join(dir, "gocfdi")
+
+	build := exec.Command("go", "build", "-o", gocfdi, "github.com/sucksens/gocfdi-transform/cmd/gocfdi")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// runCommand runs the gocfdi binary with args and returns its exit code, stdout and stderr.
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gocfdi, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	code := exitOK
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), err)
+		code = exitErr.ExitCode()
+	}
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunUsage(t *testing.T) {
+	code, _, stderr := runCommand(t)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "Usage:")
+
+	code, _, stderr = runCommand(t, "convert")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "convert"`)
+
+	code, stdout, _ := runCommand(t, "help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "gocfdi batch")
+}
+
+func TestRunParse(t *testing.T) {
+	t.Run("Imprime el JSON del CFDI", func(t *testing.T) {
+		code, stdout, _ := runCommand(t, "parse", "-pagos20", recursos+"cfdi40_pagos.xml")
+		require.Equal(t, exitOK, code)
+
+		var data struct {
+			Pagos20 []json.RawMessage `json:"pagos20"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &data))
+		assert.Len(t, data.Pagos20, 1)
+	})
+
+	t.Run("Archivo inexistente", func(t *testing.T) {
+		code, stdout, stderr := runCommand(t, "parse", filepath.Join(t.TempDir(), "no_existe.xml"))
+		assert.Equal(t, exitError, code)
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, "no_existe.xml")
+	})
+
+	t.Run("Sin archivo", func(t *testing.T) {
+		code, _, _ := runCommand(t, "parse")
+		assert.Equal(t, exitUsage, code)
+	})
+}
+
+func TestRunBatch(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cfdi40_pagos.xml", "nomina12.xml"} {
+		content, err := os.ReadFile(recursos + name)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o644))
+	}
+
+	t.Run("JSONL a un archivo", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "cfdi.jsonl")
+		code, stdout, stderr := runCommand(t, "batch", "-workers", "2", "-o", output, dir)
+		require.Equal(t, exitOK, code, stderr)
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, "2 of 2 files parsed, 0 errors")
+
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 2)
+	})
+
+	t.Run("CSV de nomina", func(t *testing.T) {
+		code, stdout, _ := runCommand(t, "batch", "-nomina12", "-format", "csv", "-table", "nomina", dir)
+		require.Equal(t, exitOK, code)
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		assert.Greater(t, len(lines), 1)
+	})
+
+	t.Run("Reporte de errores por archivo", func(t *testing.T) {
+		broken := filepath.Join(t.TempDir(), "roto.xml")
+		require.NoError(t, os.WriteFile(broken, []byte("<cfdi:Comprobante"), 0o644))
+		report := filepath.Join(t.TempDir(), "errores.jsonl")
+
+		code, _, stderr := runCommand(t, "batch", "-errors", report, broken, filepath.Join(dir, "nomina12.xml"), filepath.Join(dir, "*.txt"))
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "1 of 2 files parsed, 2 errors")
+
+		content, err := os.ReadFile(report)
+		require.NoError(t, err)
+		var entries []fileError
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			var entry fileError
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			entries = append(entries, entry)
+		}
+		require.Len(t, entries, 2)
+		assert.Equal(t, "no files matched", entries[0].Error)
+		assert.Equal(t, broken, entries[1].File)
+	})
+
+	t.Run("Flags invalidos no crean la salida", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-table", "facturas"},
+			{"-format", "xml"},
+		} {
+			output := filepath.Join(t.TempDir(), "salida.csv")
+			code, _, stderr := runCommand(t, append(append([]string{"batch", "-format", "csv", "-o", output}, args...), dir)...)
+			assert.Equal(t, exitUsage, code, stderr)
+			assert.NoFileExists(t, output)
+		}
+	})
+
+	t.Run("Sin argumentos", func(t *testing.T) {
+		code, _, _ := runCommand(t, "batch")
+		assert.Equal(t, exitUsage, code)
+	})
+}
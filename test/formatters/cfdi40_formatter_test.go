@@ -0,0 +1,107 @@
+package formatters_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/formatters"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func parseFiles(t *testing.T, paths ...string) []models.CFDI40Data {
+	t.Helper()
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseConcepts().UseConceptsWithTaxes().UsePagos20().UseNomina12()
+
+	docs := make([]models.CFDI40Data, 0, len(paths))
+	for _, path := range paths {
+		data, err := handler.TransformFromFile(path)
+		require.NoError(t, err)
+		docs = append(docs, *data)
+	}
+	return docs
+}
+
+func column(table *formatters.Table, row int, key string) string {
+	for i, col := range table.Columns {
+		if col.Key == key {
+			return table.Rows[row][i]
+		}
+	}
+	return "<missing column " + key + ">"
+}
+
+func TestCFDI40Formatter(t *testing.T) {
+	docs := parseFiles(t, "../recursos/cfdi40_pagos.xml", "../recursos/nomina12.xml")
+	formatter := formatters.NewCFDI40Formatter(formatters.NewDefaultConfig())
+
+	t.Run("Comprobantes - una fila por documento", func(t *testing.T) {
+		table := formatter.Comprobantes(docs)
+		require.Len(t, table.Rows, 2)
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", column(table, 0, "uuid"))
+		assert.Equal(t, "ESO121212R82", column(table, 0, "emisor_rfc"))
+		assert.Equal(t, "P", column(table, 0, "tipo_comprobante"))
+		assert.Equal(t, "", column(table, 1, "uuid"))
+	})
+
+	t.Run("Conceptos - una fila por concepto", func(t *testing.T) {
+		table := formatter.Conceptos(docs)
+		require.Len(t, table.Rows, 2)
+		assert.Equal(t, "84111506", column(table, 0, "clave_prod_serv"))
+	})
+
+	t.Run("Pagos y DoctosRelacionados", func(t *testing.T) {
+		pagos := formatter.Pagos(docs)
+		require.Len(t, pagos.Rows, 1)
+		assert.Equal(t, "1160.00", column(pagos, 0, "monto"))
+		assert.Equal(t, "160.00", column(pagos, 0, "iva_trasladado_p"))
+
+		doctos := formatter.DoctosRelacionados(docs)
+		require.Len(t, doctos.Rows, 1)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", column(doctos, 0, "id_documento"))
+		assert.Equal(t, "0.00", column(doctos, 0, "imp_saldo_insoluto"))
+	})
+
+	t.Run("Nomina - una fila por percepcion, deduccion y otro pago", func(t *testing.T) {
+		table := formatter.Nomina(docs)
+		require.Len(t, table.Rows, 6)
+		assert.Equal(t, "percepcion", column(table, 0, "movimiento"))
+		assert.Equal(t, "179", column(table, 0, "importe"))
+		assert.Equal(t, "deduccion", column(table, 2, "movimiento"))
+		assert.Equal(t, "otro_pago", column(table, 4, "movimiento"))
+	})
+
+	t.Run("Columnas estables aunque no haya datos", func(t *testing.T) {
+		empty := formatter.Tables(nil)
+		full := formatter.Tables(docs)
+		require.Len(t, empty, len(full))
+		for i := range empty {
+			assert.Equal(t, full[i].Columns, empty[i].Columns)
+			assert.Empty(t, empty[i].Rows)
+		}
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	docs := parseFiles(t, "../recursos/cfdi40_pagos.xml")
+
+	cfg := formatters.NewDefaultConfig()
+	cfg.EmptyChar = "-"
+	cfg.Comma = '|'
+	cfg.ColumnNames = map[string]string{"uuid": "UUID", "emisor_rfc": "RFC Emisor"}
+
+	table := formatters.NewCFDI40Formatter(cfg).Comprobantes(docs)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatters.WriteCSV(&buf, table, cfg))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "UUID|version|"))
+	assert.Contains(t, lines[0], "|RFC Emisor|")
+	assert.True(t, strings.HasPrefix(lines[1], "11111111-1111-1111-1111-111111111111|4.0|A|100|"))
+	assert.Contains(t, lines[1], "|-|")
+}
@@ -0,0 +1,104 @@
+package index_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/index"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func parseFile(t *testing.T, path string) models.CFDI40Data {
+	t.Helper()
+	data, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).TransformFromFile(path)
+	require.NoError(t, err)
+	return *data
+}
+
+func TestIndex(t *testing.T) {
+	factura := parseFile(t, "../recursos/cfdi40.xml")
+	pago := parseFile(t, "../recursos/cfdi40_pagos.xml")
+	nomina := parseFile(t, "../recursos/nomina12.xml")
+
+	ix := index.New()
+
+	status, err := ix.Add("a/cfdi40.xml", factura)
+	require.NoError(t, err)
+	assert.Equal(t, index.Added, status)
+
+	status, err = ix.Add("a/cfdi40_pagos.xml", pago)
+	require.NoError(t, err)
+	assert.Equal(t, index.Added, status)
+
+	_, err = ix.Add("a/nomina12.xml", nomina)
+	assert.ErrorIs(t, err, index.ErrMissingUUID)
+
+	t.Run("Duplicado con distinta addenda", func(t *testing.T) {
+		conAddenda := factura
+		conAddenda.CFDI40.Addendas = "requestForPayment"
+		status, err := ix.Add("b/cfdi40.xml", conAddenda)
+		require.NoError(t, err)
+		assert.Equal(t, index.Duplicate, status)
+
+		duplicates := ix.Duplicates()
+		require.Len(t, duplicates, 1)
+		assert.Equal(t, []string{"a/cfdi40.xml", "b/cfdi40.xml"}, duplicates[0].Sources)
+	})
+
+	t.Run("Conflicto con distinto contenido", func(t *testing.T) {
+		modificado := pago
+		modificado.CFDI40.Total = "999.00"
+		status, err := ix.Add("c/cfdi40_pagos.xml", modificado)
+		require.NoError(t, err)
+		assert.Equal(t, index.Conflict, status)
+
+		conflicts := ix.Conflicts()
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", conflicts[0].UUID)
+		require.Len(t, conflicts[0].Variants, 1)
+		assert.Equal(t, "c/cfdi40_pagos.xml", conflicts[0].Variants[0].Source)
+		assert.NotEqual(t, conflicts[0].Hash, conflicts[0].Variants[0].Hash)
+	})
+
+	t.Run("Consultas", func(t *testing.T) {
+		assert.Equal(t, 2, ix.Len())
+
+		entry, ok := ix.Get("a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c")
+		require.True(t, ok)
+		assert.Equal(t, "AAA010101AAA", entry.Data.CFDI40.Emisor.RFC)
+
+		assert.Len(t, ix.Query(index.Query{RfcReceptor: "xaxx010101000"}), 2)
+		assert.Len(t, ix.Query(index.Query{RfcEmisor: "ESO121212R82"}), 1)
+		assert.Len(t, ix.Query(index.Query{TipoComprobante: "P"}), 1)
+
+		all := ix.Query(index.Query{})
+		require.Len(t, all, 2)
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", all[0].UUID)
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		inRange := ix.Query(index.Query{RfcReceptor: "XAXX010101000", From: from})
+		require.Len(t, inRange, 1)
+		assert.Equal(t, "I", inRange[0].Data.CFDI40.TipoComprobante)
+
+		assert.Empty(t, ix.Query(index.Query{TipoComprobante: "I", To: from}))
+	})
+
+	t.Run("Las entradas regresadas son copias", func(t *testing.T) {
+		entry, ok := ix.Get("a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c")
+		require.True(t, ok)
+		entry.Sources[0] = "modificado.xml"
+		entry.Sources = append(entry.Sources, "extra.xml")
+
+		conflicts := ix.Conflicts()
+		require.Len(t, conflicts, 1)
+		conflicts[0].Variants[0].Source = "modificado.xml"
+
+		entry, ok = ix.Get("a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c")
+		require.True(t, ok)
+		assert.Equal(t, []string{"a/cfdi40.xml", "b/cfdi40.xml"}, entry.Sources)
+		assert.Equal(t, "c/cfdi40_pagos.xml", ix.Conflicts()[0].Variants[0].Source)
+	})
+}
@@ -0,0 +1,142 @@
+package ledger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/ledger"
+	"github.com/sucksens/gocfdi-transform/models"
+)
+
+func invoice(uuid, total string) models.CFDI40Data {
+	var doc models.CFDI40Data
+	doc.CFDI40.TipoComprobante = "I"
+	doc.CFDI40.MetodoPago = "PPD"
+	doc.CFDI40.Moneda = "MXN"
+	doc.CFDI40.Total = total
+	doc.TFD11 = []models.TFD11{{UUID: uuid}}
+	return doc
+}
+
+func payment(uuid string, doctos ...models.DoctoRelacionado20) models.CFDI40Data {
+	var doc models.CFDI40Data
+	doc.CFDI40.TipoComprobante = "P"
+	doc.TFD11 = []models.TFD11{{UUID: uuid}}
+	doc.Pagos20 = []models.Pagos20Data{{
+		Version: "2.0",
+		Pagos:   []models.Pago20{{FechaPago: "2025-01-01T00:00:00", DoctoRelacionado: doctos}},
+	}}
+	return doc
+}
+
+func docto(idDocumento, parcialidad, saldoAnt, pagado, insoluto string) models.DoctoRelacionado20 {
+	return models.DoctoRelacionado20{
+		IdDocumento:      idDocumento,
+		MonedaDR:         "MXN",
+		NumParcialidad:   parcialidad,
+		ImpSaldoAnt:      saldoAnt,
+		ImpPagado:        pagado,
+		ImpSaldoInsoluto: insoluto,
+	}
+}
+
+func issueKinds(issues []ledger.Issue, uuid string) []ledger.IssueKind {
+	var kinds []ledger.IssueKind
+	for _, issue := range issues {
+		if issue.InvoiceUUID == uuid {
+			kinds = append(kinds, issue.Kind)
+		}
+	}
+	return kinds
+}
+
+func TestLedger(t *testing.T) {
+	l := ledger.New()
+
+	// Los pagos pueden llegar antes que la factura
+	require.NoError(t, l.Add(payment("P-1", docto("a-open", "1", "1000.00", "400.00", "600.00"))))
+	require.NoError(t, l.Add(invoice("A-OPEN", "1000.00")))
+
+	require.NoError(t, l.Add(invoice("B-PAID", "500.00")))
+	require.NoError(t, l.Add(payment("P-2",
+		docto("B-PAID", "1", "500.00", "200.00", "300.00"),
+		docto("C-GAP", "1", "900.00", "300.00", "600.00"),
+	)))
+	require.NoError(t, l.Add(payment("P-3", docto("B-PAID", "2", "300.00", "300.00", "0.00"))))
+
+	require.NoError(t, l.Add(invoice("C-GAP", "900.00")))
+	require.NoError(t, l.Add(payment("P-4", docto("C-GAP", "3", "300.00", "300.00", "0.00"))))
+
+	require.NoError(t, l.Add(invoice("D-OVER", "100.00")))
+	require.NoError(t, l.Add(payment("P-5", docto("D-OVER", "1", "100.00", "100.00", "0.00"))))
+	require.NoError(t, l.Add(payment("P-6", docto("D-OVER", "1", "100.00", "100.00", "0.00"))))
+
+	require.NoError(t, l.Add(payment("P-7", docto("Z-UNKNOWN", "1", "50.00", "50.00", "0.00"))))
+
+	report := l.Report()
+
+	t.Run("Saldos por factura", func(t *testing.T) {
+		require.Len(t, report.Invoices, 4)
+		open, ok := l.Invoice("a-open")
+		require.True(t, ok)
+		assert.Equal(t, "400.00", open.Paid.StringFixed(2))
+		assert.Equal(t, "600.00", open.Balance.StringFixed(2))
+		require.Len(t, open.Payments, 1)
+		assert.Equal(t, "P-1", open.Payments[0].UUID)
+
+		paid, _ := l.Invoice("B-PAID")
+		assert.True(t, paid.Balance.IsZero())
+		assert.Equal(t, []int{1, 2}, []int{paid.Payments[0].NumParcialidad, paid.Payments[1].NumParcialidad})
+	})
+
+	t.Run("Facturas con saldo abierto", func(t *testing.T) {
+		var uuids []string
+		for _, invoice := range report.Open {
+			uuids = append(uuids, invoice.UUID)
+		}
+		assert.Equal(t, []string{"A-OPEN", "C-GAP"}, uuids)
+	})
+
+	t.Run("Inconsistencias", func(t *testing.T) {
+		assert.Empty(t, issueKinds(report.Issues, "A-OPEN"))
+		assert.Empty(t, issueKinds(report.Issues, "B-PAID"))
+		assert.Equal(t, []ledger.IssueKind{ledger.ParcialidadGap, ledger.SaldoMismatch}, issueKinds(report.Issues, "C-GAP"))
+		assert.Equal(t, []ledger.IssueKind{ledger.DuplicateParcialidad, ledger.SaldoMismatch, ledger.Overpayment}, issueKinds(report.Issues, "D-OVER"))
+		assert.Equal(t, []ledger.IssueKind{ledger.UnknownInvoice}, issueKinds(report.Issues, "Z-UNKNOWN"))
+	})
+
+	t.Run("Solo facturas PPD con UUID", func(t *testing.T) {
+		pue := invoice("E-PUE", "100.00")
+		pue.CFDI40.MetodoPago = "PUE"
+		assert.ErrorIs(t, l.AddInvoice(pue), ledger.ErrNotPPD)
+
+		egreso := invoice("F-EGRESO", "100.00")
+		egreso.CFDI40.TipoComprobante = "E"
+
+		// Add ignora los documentos que no son de la cartera
+		require.NoError(t, l.Add(pue))
+		require.NoError(t, l.Add(egreso))
+		_, ok := l.Invoice("E-PUE")
+		assert.False(t, ok)
+		assert.Len(t, l.Report().Invoices, 4)
+
+		sinTimbre := invoice("", "100.00")
+		assert.ErrorIs(t, l.Add(sinTimbre), ledger.ErrMissingUUID)
+	})
+
+	t.Run("Un recibo agregado dos veces se cuenta una vez", func(t *testing.T) {
+		l := ledger.New()
+		require.NoError(t, l.Add(invoice("G-PARCIAL", "1000.00")))
+		recibo := payment("p-8", docto("G-PARCIAL", "1", "1000.00", "400.00", "600.00"))
+		require.NoError(t, l.Add(recibo))
+		require.NoError(t, l.Add(recibo))
+		l.AddPagos("P-8", recibo.Pagos20[0])
+
+		parcial, ok := l.Invoice("G-PARCIAL")
+		require.True(t, ok)
+		require.Len(t, parcial.Payments, 1)
+		assert.Equal(t, "600.00", parcial.Balance.StringFixed(2))
+		assert.Empty(t, l.Report().Issues)
+	})
+}
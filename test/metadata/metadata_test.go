@@ -0,0 +1,92 @@
+package metadata_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/metadata"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+const metadataTXT = "\ufeffUuid~RfcEmisor~NombreEmisor~RfcReceptor~NombreReceptor~RfcPac~FechaEmision~FechaCertificacionSat~Monto~EfectoComprobante~Estatus~FechaCancelacion\r\n" +
+	"a3c6a0d7-8f4b-4e2a-9b5c-1d8e9f7a6b2c~AAA010101AAA~EMISOR DE PRUEBA SA DE CV~XAXX010101000~PUBLICO EN GENERAL~AAA010101AAA~2025-01-15 10:30:00~2025-01-15 10:30:01~1160.00~I~1~\r\n" +
+	"11111111-1111-1111-1111-111111111111~ESO121212R82~EMPRESA DE SERVICIOS ONLINE~XAXX010101000~PUBLICO EN GENERAL~SAT970701NN3~2023-10-27 12:00:00~2023-10-27 12:05:00~10.00~P~0~2023-11-01 09:00:00\r\n" +
+	"\r\n" +
+	"22222222-2222-2222-2222-222222222222~AAA010101AAA~EMISOR DE PRUEBA SA DE CV~XAXX010101000~PUBLICO EN GENERAL~AAA010101AAA~2025-02-01 08:00:00~2025-02-01 08:00:05~500.00~E~1~\r\n"
+
+func TestReader(t *testing.T) {
+	t.Run("Lee registros tipados", func(t *testing.T) {
+		records, err := metadata.NewReader(strings.NewReader(metadataTXT)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+
+		r := records[0]
+		assert.Equal(t, "A3C6A0D7-8F4B-4E2A-9B5C-1D8E9F7A6B2C", r.UUID)
+		assert.Equal(t, "EMISOR DE PRUEBA SA DE CV", r.NombreEmisor)
+		assert.Equal(t, 2025, r.FechaEmision.Year())
+		assert.Equal(t, "1160", r.Monto.String())
+		assert.Equal(t, metadata.EstatusVigente, r.Estatus)
+		assert.True(t, r.FechaCancelacion.IsZero())
+
+		assert.True(t, records[1].Cancelado())
+		assert.Equal(t, 11, int(records[1].FechaCancelacion.Month()))
+	})
+
+	t.Run("Sin encabezado usa el orden por defecto", func(t *testing.T) {
+		lines := strings.SplitN(metadataTXT, "\n", 2)
+		reader := metadata.NewReader(strings.NewReader(lines[1]))
+		record, err := reader.Read()
+		require.NoError(t, err)
+		assert.Equal(t, "AAA010101AAA", record.RfcEmisor)
+	})
+
+	t.Run("Error con numero de linea", func(t *testing.T) {
+		reader := metadata.NewReader(strings.NewReader("Uuid~Estatus\nX~1\nY~9\n"))
+		_, err := reader.Read()
+		require.NoError(t, err)
+
+		_, err = reader.Read()
+		var parseErr *metadata.ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 3, parseErr.Line)
+
+		_, err = reader.Read()
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	records, err := metadata.NewReader(strings.NewReader(metadataTXT)).ReadAll()
+	require.NoError(t, err)
+
+	handler := sax.NewCFDI40Handler(sax.NewDefaultConfig())
+	var docs []models.CFDI40Data
+	for _, path := range []string{"../recursos/cfdi40.xml", "../recursos/cfdi40_pagos.xml", "../recursos/nomina12.xml"} {
+		data, err := handler.TransformFromFile(path)
+		require.NoError(t, err)
+		docs = append(docs, *data)
+	}
+	extra := docs[0]
+	extra.TFD11 = []models.TFD11{{UUID: "33333333-3333-3333-3333-333333333333"}}
+	docs = append(docs, extra)
+
+	report := metadata.Reconcile(records, docs)
+
+	require.Len(t, report.MissingXML, 1)
+	assert.Equal(t, "22222222-2222-2222-2222-222222222222", report.MissingXML[0].UUID)
+
+	assert.Equal(t, []string{"33333333-3333-3333-3333-333333333333"}, report.MissingMetadata)
+
+	require.Len(t, report.Cancelled, 1)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", report.Cancelled[0].UUID)
+
+	require.Len(t, report.AmountMismatches, 1)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", report.AmountMismatches[0].UUID)
+	assert.Equal(t, "10", report.AmountMismatches[0].Metadata.String())
+	assert.Equal(t, "0", report.AmountMismatches[0].CFDI.String())
+}
@@ -0,0 +1,98 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/money"
+)
+
+func document(moneda, tipoCambio string) models.CFDI40Data {
+	var doc models.CFDI40Data
+	doc.CFDI40.Fecha = "2025-03-10T10:00:00"
+	doc.CFDI40.Moneda = moneda
+	doc.CFDI40.TipoCambio = tipoCambio
+	return doc
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, "10.13", money.Round(decimal.RequireFromString("10.125"), "MXN").String())
+	assert.Equal(t, "1235", money.Round(decimal.RequireFromString("1234.5"), "JPY").String())
+	assert.Equal(t, "1.235", money.Round(decimal.RequireFromString("1.2345"), "KWD").String())
+	assert.Equal(t, int32(2), money.Decimals("usd"))
+}
+
+func TestConverterDocument(t *testing.T) {
+	converter := money.NewConverter(nil)
+
+	t.Run("Con el TipoCambio del documento", func(t *testing.T) {
+		doc := document("USD", "17.1234")
+		mxn, err := converter.DocumentToMXN(doc, "100.00")
+		require.NoError(t, err)
+		assert.Equal(t, "1712.34", mxn.StringFixed(2))
+
+		usd, err := converter.MXNToDocument(doc, "1712.34")
+		require.NoError(t, err)
+		assert.Equal(t, "100.00", usd.StringFixed(2))
+	})
+
+	t.Run("MXN no requiere tipo de cambio", func(t *testing.T) {
+		mxn, err := converter.DocumentToMXN(document("MXN", ""), "99.999")
+		require.NoError(t, err)
+		assert.Equal(t, "100", mxn.String())
+	})
+
+	t.Run("Sin TipoCambio se usa la tabla", func(t *testing.T) {
+		doc := document("EUR", "")
+		_, err := converter.DocumentToMXN(doc, "10")
+		assert.ErrorIs(t, err, money.ErrMissingRate)
+
+		rates := money.DailyRates{"EUR": {"2025-03-10": decimal.RequireFromString("19.50")}}
+		mxn, err := money.NewConverter(rates).DocumentToMXN(doc, "10")
+		require.NoError(t, err)
+		assert.Equal(t, "195.00", mxn.StringFixed(2))
+
+		// El 1.00 de SafeNumerics en moneda extranjera se reemplaza por la tabla
+		mxn, err = money.NewConverter(rates).DocumentToMXN(document("EUR", "1.00"), "10")
+		require.NoError(t, err)
+		assert.Equal(t, "195.00", mxn.StringFixed(2))
+	})
+
+	t.Run("Importe invalido", func(t *testing.T) {
+		_, err := converter.DocumentToMXN(document("MXN", ""), "N/A")
+		assert.ErrorIs(t, err, money.ErrInvalidAmount)
+	})
+}
+
+func TestConverterPagos(t *testing.T) {
+	converter := money.NewConverter(nil)
+	pago := models.Pago20{FechaPago: "2025-03-10T10:00:00", MonedaP: "USD", TipoCambioP: "20.00", Monto: "50.00"}
+	docto := models.DoctoRelacionado20{IdDocumento: "A", MonedaDR: "EUR", EquivalenciaDR: "0.920000", ImpPagado: "46.00"}
+
+	mxn, err := converter.PagoToMXN(pago, pago.Monto)
+	require.NoError(t, err)
+	assert.Equal(t, "1000.00", mxn.StringFixed(2))
+
+	usd, err := converter.DoctoToPago(pago, docto, docto.ImpPagado)
+	require.NoError(t, err)
+	assert.Equal(t, "50.00", usd.StringFixed(2))
+
+	mxn, err = converter.DoctoToMXN(pago, docto, docto.ImpPagado)
+	require.NoError(t, err)
+	assert.Equal(t, "1000.00", mxn.StringFixed(2))
+
+	// Factura en MXN pagada en USD
+	doctoMXN := models.DoctoRelacionado20{IdDocumento: "B", MonedaDR: "MXN", EquivalenciaDR: "20.00", ImpPagado: "1000.00"}
+	usd, err = converter.DoctoToPago(pago, doctoMXN, doctoMXN.ImpPagado)
+	require.NoError(t, err)
+	assert.Equal(t, "50.00", usd.StringFixed(2))
+	mxn, err = converter.DoctoToMXN(pago, doctoMXN, doctoMXN.ImpPagado)
+	require.NoError(t, err)
+	assert.Equal(t, "1000.00", mxn.StringFixed(2))
+
+	_, err = converter.DoctoToPago(pago, models.DoctoRelacionado20{IdDocumento: "C", MonedaDR: "EUR"}, "1")
+	assert.ErrorIs(t, err, money.ErrMissingRate)
+}
@@ -0,0 +1,139 @@
+package payroll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/payroll"
+	"github.com/sucksens/gocfdi-transform/sax"
+)
+
+func nominaFromFile(t *testing.T, uuid string) models.CFDI40Data {
+	t.Helper()
+	doc, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseNomina12().TransformFromFile("../recursos/nomina12.xml")
+	require.NoError(t, err)
+	doc.TFD11 = []models.TFD11{{UUID: uuid}}
+	return *doc
+}
+
+func nomina(numEmpleado, fechaPago, dias, isr string) models.CFDI40Data {
+	var doc models.CFDI40Data
+	doc.Nomina12 = []models.Nomina12Data{{
+		Version:          "1.2",
+		TipoNomina:       "O",
+		FechaPago:        fechaPago,
+		FechaInicialPago: fechaPago,
+		FechaFinalPago:   fechaPago,
+		NumDiasPagados:   dias,
+		Receptor:         models.Nomina12Receptor{NumEmpleado: numEmpleado, PeriodicidadPago: "02"},
+		Percepciones: models.Nomina12Percepciones{Percepcion: []models.Nomina12Percepcion{
+			{TipoPercepcion: "001", ImporteGravado: "1000.00", ImporteExento: "0.00"},
+		}},
+		Deducciones: models.Nomina12Deducciones{Deduccion: []models.Nomina12Deduccion{
+			{TipoDeduccion: payroll.DeduccionISR, Importe: isr},
+		}},
+	}}
+	return doc
+}
+
+func TestAggregatorFromFile(t *testing.T) {
+	agg := payroll.New()
+	require.NoError(t, agg.Add(nominaFromFile(t, "uuid-1")))
+	require.NoError(t, agg.Add(nominaFromFile(t, "uuid-2")))
+	// El mismo UUID no se acumula dos veces
+	require.NoError(t, agg.Add(nominaFromFile(t, "UUID-1")))
+
+	summary, ok := agg.Get("aaa010101aaa", "oaaj840102hjcvrn00", "2016")
+	require.True(t, ok)
+	assert.Equal(t, "OAAJ840102HJCVRN00", summary.Curp)
+	assert.Equal(t, "AAA010101AAA", summary.RfcEmisor)
+	assert.Equal(t, "BASJ600902KL9", summary.RfcReceptor)
+	assert.Equal(t, 2, summary.Receipts)
+	assert.Equal(t, "356", summary.TotalGravado.String())
+	assert.Equal(t, "360", summary.TotalExento.String())
+	assert.Equal(t, "4938.24", summary.TotalOtrosPagos.String())
+	assert.Equal(t, "5654.24", summary.Gross.String())
+	assert.Equal(t, "20", summary.IMSS.String())
+	assert.True(t, summary.ISRRetenido.IsZero())
+	assert.Equal(t, "4938.24", summary.SubsidioCausado.String())
+	assert.Equal(t, "2469.12", summary.SubsidioEntregado.String())
+	assert.Equal(t, "30", summary.DiasPagados.String())
+
+	require.Len(t, summary.Periods, 2)
+	assert.Equal(t, "2016-10-01", summary.Periods[0].FechaInicialPago)
+	assert.Equal(t, "2016-10-15", summary.Periods[0].FechaFinalPago)
+	assert.Equal(t, "04", summary.Periods[0].PeriodicidadPago)
+	assert.Equal(t, "15", summary.Periods[0].NumDiasPagados.String())
+
+	_, ok = agg.Get("AAA010101AAA", "OAAJ840102HJCVRN00", "2017")
+	assert.False(t, ok)
+}
+
+func TestAggregatorByNumEmpleadoAndYear(t *testing.T) {
+	agg := payroll.New()
+	require.NoError(t, agg.Add(nomina("7", "2024-12-31", "14", "150.00")))
+	require.NoError(t, agg.Add(nomina("7", "2025-01-15", "15", "160.00")))
+	require.NoError(t, agg.Add(nomina("7", "2025-01-31", "16", "170.00")))
+	require.NoError(t, agg.Add(nomina("3", "2025-01-31", "16", "100.00")))
+
+	summaries := agg.Summaries()
+	require.Len(t, summaries, 3)
+	assert.Equal(t, payroll.Key{NumEmpleado: "7", Year: "2024"}, summaries[0].Key)
+	assert.Equal(t, payroll.Key{NumEmpleado: "3", Year: "2025"}, summaries[1].Key)
+	assert.Equal(t, payroll.Key{NumEmpleado: "7", Year: "2025"}, summaries[2].Key)
+
+	summary, ok := agg.Get("", "7", "2025")
+	require.True(t, ok)
+	assert.Equal(t, 2, summary.Receipts)
+	assert.Equal(t, "2000", summary.Gross.String())
+	assert.Equal(t, "330", summary.ISRRetenido.String())
+	assert.Equal(t, "31", summary.DiasPagados.String())
+
+	assert.Len(t, agg.Year("2025"), 2)
+}
+
+func TestAggregatorByEmployer(t *testing.T) {
+	agg := payroll.New()
+	primero := nomina("7", "2025-01-15", "15", "160.00")
+	primero.CFDI40.Emisor.RFC = "AAA010101AAA"
+	segundo := nomina("7", "2025-01-15", "15", "90.00")
+	segundo.CFDI40.Emisor.RFC = "bbb010101bbb"
+	require.NoError(t, agg.Add(primero))
+	require.NoError(t, agg.Add(segundo))
+
+	summaries := agg.Summaries()
+	require.Len(t, summaries, 2)
+	assert.Equal(t, payroll.Key{RfcEmisor: "AAA010101AAA", NumEmpleado: "7", Year: "2025"}, summaries[0].Key)
+	assert.Equal(t, payroll.Key{RfcEmisor: "BBB010101BBB", NumEmpleado: "7", Year: "2025"}, summaries[1].Key)
+
+	summary, ok := agg.Get("BBB010101BBB", "7", "2025")
+	require.True(t, ok)
+	assert.Equal(t, "90", summary.ISRRetenido.String())
+}
+
+func TestAggregatorExtraordinaria(t *testing.T) {
+	doc, err := sax.NewCFDI40Handler(sax.NewDefaultConfig()).UseNomina12().TransformFromFile("../recursos/nomina12_multiple.xml")
+	require.NoError(t, err)
+
+	agg := payroll.New()
+	require.NoError(t, agg.Add(*doc))
+
+	summary, ok := agg.Get("EKU9003173C9", "XOJI740919MJCDMN05", "2025")
+	require.True(t, ok)
+	assert.Equal(t, 2, summary.Receipts)
+	// La nomina extraordinaria no agrega dias pagados
+	assert.Equal(t, "16", summary.DiasPagados.String())
+	require.Len(t, summary.Periods, 2)
+	assert.Equal(t, "E", summary.Periods[0].TipoNomina)
+	assert.Equal(t, "1", summary.Periods[0].NumDiasPagados.String())
+}
+
+func TestAggregatorErrors(t *testing.T) {
+	agg := payroll.New()
+	assert.ErrorIs(t, agg.Add(models.CFDI40Data{}), payroll.ErrNoNomina)
+	assert.ErrorIs(t, agg.Add(nomina("", "2025-01-15", "15", "0")), payroll.ErrMissingEmployee)
+	assert.ErrorIs(t, agg.Add(nomina("1", "", "15", "0")), payroll.ErrInvalidFechaPago)
+	assert.Empty(t, agg.Summaries())
+}
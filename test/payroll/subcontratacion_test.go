@@ -0,0 +1,100 @@
+package payroll_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/models"
+	"github.com/sucksens/gocfdi-transform/payroll"
+)
+
+func subcontratada(numEmpleado, dias string, subs ...models.Subcontratacion) models.CFDI40Data {
+	doc := nomina(numEmpleado, "2025-01-15", dias, "0.00")
+	doc.Nomina12[0].Receptor.Subcontrataciones = subs
+	return doc
+}
+
+func TestAssignmentsFromFile(t *testing.T) {
+	assignments := payroll.Assignments(nominaFromFile(t, "uuid-1"), decimal.Zero)
+	require.Len(t, assignments, 2)
+
+	assert.Equal(t, "UUID-1", assignments[0].UUID)
+	assert.Equal(t, "AAA010101AAA", assignments[0].RfcLabora)
+	assert.Equal(t, "OAAJ840102HJCVRN00", assignments[0].Curp)
+	assert.Equal(t, "3.5175", assignments[0].DiasLaborados.String())
+	assert.Equal(t, "28.14", assignments[0].Horas.String())
+	assert.Equal(t, "83.95", assignments[0].Percepciones.String())
+
+	assert.Equal(t, "BBB010101AAA", assignments[1].RfcLabora)
+	assert.Equal(t, "16.14", assignments[1].Horas.String())
+	assert.Equal(t, "48.15", assignments[1].Percepciones.String())
+}
+
+func TestSubcontratacionReports(t *testing.T) {
+	docs := []models.CFDI40Data{
+		subcontratada("1", "15",
+			models.Subcontratacion{RfcLabora: "bbb010101aaa", PorcentajeTiempo: "50"},
+			models.Subcontratacion{RfcLabora: "AAA010101AAA", PorcentajeTiempo: "50"}),
+		subcontratada("2", "10",
+			models.Subcontratacion{RfcLabora: "AAA010101AAA", PorcentajeTiempo: "100"}),
+		// Sin SubContratacion no se asigna a ningun cliente
+		subcontratada("3", "15"),
+	}
+
+	hours := payroll.HoursByRfcLabora(docs, decimal.NewFromInt(6))
+	require.Len(t, hours, 2)
+	assert.Equal(t, "105", hours["AAA010101AAA"].String())
+	assert.Equal(t, "45", hours["BBB010101AAA"].String())
+
+	reports := payroll.ClientReports(docs, decimal.Zero)
+	require.Len(t, reports, 2)
+
+	aaa := reports[0]
+	assert.Equal(t, "AAA010101AAA", aaa.RfcLabora)
+	assert.Equal(t, []string{"1", "2"}, aaa.Employees)
+	assert.Equal(t, 2, aaa.Receipts)
+	assert.Equal(t, "17.5", aaa.DiasLaborados.String())
+	assert.Equal(t, "140", aaa.Horas.String())
+	assert.Equal(t, "1500", aaa.Percepciones.String())
+	assert.Len(t, aaa.Assignments, 2)
+
+	bbb := reports[1]
+	assert.Equal(t, "BBB010101AAA", bbb.RfcLabora)
+	assert.Equal(t, []string{"1"}, bbb.Employees)
+	assert.Equal(t, 1, bbb.Receipts)
+	assert.Equal(t, "60", bbb.Horas.String())
+}
+
+func TestSubcontratacionDuplicadosYExtraordinarias(t *testing.T) {
+	timbrada := subcontratada("1", "10", models.Subcontratacion{RfcLabora: "AAA010101AAA", PorcentajeTiempo: "100"})
+	timbrada.TFD11 = []models.TFD11{{UUID: "uuid-1"}}
+	repetida := timbrada
+	repetida.TFD11 = []models.TFD11{{UUID: "UUID-1"}}
+
+	extraordinaria := subcontratada("2", "1", models.Subcontratacion{RfcLabora: "AAA010101AAA", PorcentajeTiempo: "100"})
+	extraordinaria.Nomina12[0].TipoNomina = payroll.NominaExtraordinaria
+	assert.Empty(t, payroll.Assignments(extraordinaria, decimal.Zero))
+
+	docs := []models.CFDI40Data{timbrada, repetida, extraordinaria}
+	reports := payroll.ClientReports(docs, decimal.Zero)
+	require.Len(t, reports, 1)
+	assert.Equal(t, 1, reports[0].Receipts)
+	assert.Equal(t, []string{"1"}, reports[0].Employees)
+	assert.Equal(t, "80", reports[0].Horas.String())
+	assert.Len(t, reports[0].Assignments, 1)
+
+	assert.Equal(t, "80", payroll.HoursByRfcLabora(docs, decimal.Zero)["AAA010101AAA"].String())
+}
+
+func TestSummaryEntidadSNCF(t *testing.T) {
+	agg := payroll.New()
+	require.NoError(t, agg.Add(nominaFromFile(t, "uuid-1")))
+	require.NoError(t, agg.Add(nominaFromFile(t, "uuid-2")))
+
+	summary, ok := agg.Get("AAA010101AAA", "OAAJ840102HJCVRN00", "2016")
+	require.True(t, ok)
+	assert.Equal(t, "IP", summary.OrigenRecurso)
+	assert.Equal(t, "246.9", summary.MontoRecursoPropio.String())
+}
@@ -0,0 +1,168 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sucksens/gocfdi-transform/sax"
+	"github.com/sucksens/gocfdi-transform/schema"
+)
+
+func TestSchemaFilesInSync(t *testing.T) {
+	for file, generate := range map[string]func() ([]byte, error){
+		"../../schema/cfdi.schema.json": schema.JSONSchema,
+		"../../schema/openapi.json":     schema.OpenAPIComponents,
+	} {
+		expected, err := generate()
+		require.NoError(t, err)
+		shipped, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(shipped), "%s is out of sync with models, run go generate ./schema", file)
+	}
+}
+
+func TestParsedDocumentsMatchSchema(t *testing.T) {
+	content, err := schema.JSONSchema()
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &doc))
+	defs := doc["$defs"].(map[string]interface{})
+
+	config := sax.NewDefaultConfig()
+	config.ParseConcepts = true
+	config.ParseConceptsTaxes = true
+	config.ParseRelatedCFDIs = true
+	config.ParsePagos20 = true
+	config.ParseNomina12 = true
+	config.ParseRawComplements = true
+	config.ParseAddendas = true
+
+	for _, file := range []string{"cfdi40.xml", "cfdi40_pagos.xml", "nomina12.xml", "nomina12_multiple.xml"} {
+		t.Run(file, func(t *testing.T) {
+			data, err := sax.NewCFDI40Handler(config).TransformFromFile("../recursos/" + file)
+			require.NoError(t, err)
+
+			encoded, err := json.Marshal(data)
+			require.NoError(t, err)
+			var value interface{}
+			require.NoError(t, json.Unmarshal(encoded, &value))
+
+			assert.NoError(t, validate(defs, doc, value, "$"))
+		})
+	}
+
+	t.Run("cfdi33_pagos10.xml", func(t *testing.T) {
+		data, err := sax.NewPagos10Handler(config).TransformFromBytes(mustRead(t, "../recursos/cfdi33_pagos10.xml"))
+		require.NoError(t, err)
+
+		encoded, err := json.Marshal(data)
+		require.NoError(t, err)
+		var value interface{}
+		require.NoError(t, json.Unmarshal(encoded, &value))
+
+		assert.NoError(t, validate(defs, map[string]interface{}{"$ref": "#/$defs/Pagos10Data"}, value, "$"))
+	})
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
+
+// validate checks value against the subset of JSON Schema produced by the schema package.
+func validate(defs map[string]interface{}, s map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		return validate(defs, defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}), value, path)
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if validate(defs, option.(map[string]interface{}), value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: no anyOf option matches", path)
+	}
+	if !typeMatches(s["type"], value) {
+		return fmt.Errorf("%s: %T does not match type %v", path, value, s["type"])
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := s["properties"].(map[string]interface{})
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required %s", path, name)
+			}
+		}
+		for name, item := range v {
+			property, ok := properties[name]
+			if !ok {
+				if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+					property = additional
+				} else {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+			}
+			if err := validate(defs, property.(map[string]interface{}), item, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := validate(defs, s["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func typeMatches(schemaType interface{}, value interface{}) bool {
+	var types []interface{}
+	switch st := schemaType.(type) {
+	case nil:
+		return true
+	case string:
+		types = []interface{}{st}
+	case []interface{}:
+		types = st
+	}
+
+	for _, st := range types {
+		switch value.(type) {
+		case nil:
+			if st == "null" {
+				return true
+			}
+		case string:
+			if st == "string" {
+				return true
+			}
+		case bool:
+			if st == "boolean" {
+				return true
+			}
+		case float64:
+			if st == "number" || st == "integer" {
+				return true
+			}
+		case []interface{}:
+			if st == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if st == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}